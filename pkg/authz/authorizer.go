@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+// Authorizerer authorizes gRPC methods and Gin routes
+type Authorizerer interface {
+	AuthorizeMethod(fullMethod string, claims *jwt.TokenClaims) error
+	AuthorizeRoute(httpMethod, routePath string, claims *jwt.TokenClaims) error
+}
+
+// Authorizer evaluates the requirements of a policy
+type Authorizer struct {
+	defaultRequirement Requirement
+	methods            map[string]Requirement
+	routes             map[string]Requirement
+}
+
+var _ Authorizerer = &Authorizer{}
+
+// NewAuthorizer creates a new authorizer, every rule must have either a method or a route
+func NewAuthorizer(policy *Policy) (*Authorizer, error) {
+	authorizer := &Authorizer{
+		defaultRequirement: policy.Default,
+		methods:            map[string]Requirement{},
+		routes:             map[string]Requirement{},
+	}
+	for _, rule := range policy.Rules {
+		switch {
+		case rule.Method != "" && rule.Route == "":
+			if _, exists := authorizer.methods[rule.Method]; exists {
+				return nil, fmt.Errorf("Duplicated authorization rule for method %s", rule.Method)
+			}
+			authorizer.methods[rule.Method] = rule.Requirement
+		case rule.Route != "" && rule.Method == "":
+			route, err := normalizeRoute(rule.Route)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := authorizer.routes[route]; exists {
+				return nil, fmt.Errorf("Duplicated authorization rule for route %s", rule.Route)
+			}
+			authorizer.routes[route] = rule.Requirement
+		default:
+			return nil, fmt.Errorf("Authorization rule must have either a method or a route: %+v", rule)
+		}
+	}
+	return authorizer, nil
+}
+
+func normalizeRoute(route string) (string, error) {
+	fields := strings.Fields(route)
+	if len(fields) != 2 {
+		return "", fmt.Errorf("Authorization route %s is not of the form <HTTP method> <path>", route)
+	}
+	return routeKey(fields[0], fields[1]), nil
+}
+
+func routeKey(httpMethod, routePath string) string {
+	return strings.ToUpper(httpMethod) + " " + routePath
+}
+
+// AuthorizeMethod checks the claims satisfy the requirement of the gRPC full method name
+func (authorizer *Authorizer) AuthorizeMethod(fullMethod string, claims *jwt.TokenClaims) error {
+	requirement, exists := authorizer.methods[fullMethod]
+	if !exists {
+		requirement = authorizer.defaultRequirement
+	}
+	return requirement.Check(claims)
+}
+
+// AuthorizeRoute checks the claims satisfy the requirement of the Gin route
+func (authorizer *Authorizer) AuthorizeRoute(httpMethod, routePath string, claims *jwt.TokenClaims) error {
+	requirement, exists := authorizer.routes[routeKey(httpMethod, routePath)]
+	if !exists {
+		requirement = authorizer.defaultRequirement
+	}
+	return requirement.Check(claims)
+}
@@ -0,0 +1,136 @@
+package authz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+const (
+	testPublicMethod = "/pb_authentication.AuthenticationService/Authenticate"
+	testAdminMethod  = "/pb_authentication.AuthenticationService/DeleteUser"
+	testOtherMethod  = "/pb_authentication.AuthenticationService/GetUserProfile"
+	testPolicyYAML   = `
+authorization:
+  default:
+    scopes: [user:read]
+  rules:
+    - method: /pb_authentication.AuthenticationService/Authenticate
+      public: true
+    - method: /pb_authentication.AuthenticationService/DeleteUser
+      scopes: [user:read, user:write]
+      roles: [admin, support]
+    - route: delete /users/:id
+      roles: [admin]
+`
+)
+
+func loadTestPolicy(t *testing.T) *Policy {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	if err := config.ReadConfig(strings.NewReader(testPolicyYAML)); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadPolicy(config, PolicyConfigKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func newTestAuthorizer(t *testing.T) *Authorizer {
+	authorizer, err := NewAuthorizer(loadTestPolicy(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authorizer
+}
+
+func assertStatusCode(t *testing.T, code codes.Code, err error) {
+	statusError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, code, statusError.Code())
+}
+
+func TestLoadPolicy(t *testing.T) {
+	policy := loadTestPolicy(t)
+
+	assert.Equal(t, []string{"user:read"}, policy.Default.Scopes)
+	assert.Len(t, policy.Rules, 3)
+	assert.Equal(t, testPublicMethod, policy.Rules[0].Method)
+	assert.True(t, policy.Rules[0].Public)
+	assert.Equal(t, []string{"admin", "support"}, policy.Rules[1].Roles)
+	assert.Equal(t, "delete /users/:id", policy.Rules[2].Route)
+}
+
+func TestAuthorizer(t *testing.T) {
+	authorizer := newTestAuthorizer(t)
+
+	t.Run("Public_Method", func(t *testing.T) {
+		assert.NoError(t, authorizer.AuthorizeMethod(testPublicMethod, nil))
+	})
+
+	t.Run("Missing_Claims", func(t *testing.T) {
+		assertStatusCode(t, codes.Unauthenticated, authorizer.AuthorizeMethod(testOtherMethod, nil))
+	})
+
+	t.Run("Default_Requirement", func(t *testing.T) {
+		assert.NoError(t, authorizer.AuthorizeMethod(testOtherMethod, &jwt.TokenClaims{Scopes: []string{"user:read"}}))
+		assertStatusCode(t, codes.PermissionDenied, authorizer.AuthorizeMethod(testOtherMethod, &jwt.TokenClaims{}))
+	})
+
+	t.Run("All_Scopes_Required", func(t *testing.T) {
+		err := authorizer.AuthorizeMethod(testAdminMethod, &jwt.TokenClaims{
+			Scopes: []string{"user:read"},
+			Roles:  []string{"admin"},
+		})
+
+		assertStatusCode(t, codes.PermissionDenied, err)
+		assert.Contains(t, status.Convert(err).Message(), "user:write")
+	})
+
+	t.Run("Any_Role_Required", func(t *testing.T) {
+		scopes := []string{"user:read", "user:write"}
+		assert.NoError(t, authorizer.AuthorizeMethod(testAdminMethod, &jwt.TokenClaims{
+			Scopes: scopes,
+			Roles:  []string{"support"},
+		}))
+
+		err := authorizer.AuthorizeMethod(testAdminMethod, &jwt.TokenClaims{
+			Scopes: scopes,
+			Roles:  []string{"user"},
+		})
+		assertStatusCode(t, codes.PermissionDenied, err)
+		assert.Contains(t, status.Convert(err).Message(), "admin, support")
+	})
+
+	t.Run("Route", func(t *testing.T) {
+		assert.NoError(t, authorizer.AuthorizeRoute("DELETE", "/users/:id", &jwt.TokenClaims{Roles: []string{"admin"}}))
+		assertStatusCode(
+			t,
+			codes.PermissionDenied,
+			authorizer.AuthorizeRoute("DELETE", "/users/:id", &jwt.TokenClaims{Scopes: []string{"user:read"}}),
+		)
+		assert.NoError(t, authorizer.AuthorizeRoute("GET", "/users/:id", &jwt.TokenClaims{Scopes: []string{"user:read"}}))
+	})
+}
+
+func TestNewAuthorizerInvalidPolicy(t *testing.T) {
+	_, err := NewAuthorizer(&Policy{Rules: []Rule{{}}})
+	assert.Error(t, err)
+
+	_, err = NewAuthorizer(&Policy{Rules: []Rule{{Method: testAdminMethod, Route: "GET /users"}}})
+	assert.Error(t, err)
+
+	_, err = NewAuthorizer(&Policy{Rules: []Rule{{Route: "/users"}}})
+	assert.Error(t, err)
+
+	_, err = NewAuthorizer(&Policy{Rules: []Rule{{Method: testAdminMethod}, {Method: testAdminMethod}}})
+	assert.Error(t, err)
+}
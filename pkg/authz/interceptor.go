@@ -0,0 +1,62 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	"github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+func getClaims(ctx context.Context) *jwt.TokenClaims {
+	// Missing claims are rejected by non public requirements
+	claims, _ := jwt.GetClaimsFromContext(ctx)
+	return claims
+}
+
+// CreateAuthorizationInterceptor is the interceptor that authorizes the gRPC calls,
+// it must be chained after the jwt authentication interceptor
+func CreateAuthorizationInterceptor(authorizer Authorizerer) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := authorizer.AuthorizeMethod(info.FullMethod, getClaims(ctx)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// CreateAuthorizationStreamInterceptor is the interceptor that authorizes the streaming gRPC calls,
+// it must be chained after the jwt authentication stream interceptor
+func CreateAuthorizationStreamInterceptor(authorizer Authorizerer) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := authorizer.AuthorizeMethod(info.FullMethod, getClaims(stream.Context())); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// CreateGinAuthorizationMiddleware is the middleware that authorizes the requests by their route,
+// it must be used after the jwt authentication middleware
+func CreateGinAuthorizationMiddleware(authorizer Authorizerer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Missing claims are rejected by non public requirements
+		claims, _ := jwt.GetClaimsFromGinContext(c)
+		if err := authorizer.AuthorizeRoute(c.Request.Method, c.FullPath(), claims); err != nil {
+			jwt.AbortWithStatusError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func contextWithClaims(claims *jwt.TokenClaims) context.Context {
+	return context.WithValue(context.Background(), jwt.ClaimsContextKey, claims)
+}
+
+func TestAuthorizationInterceptor(t *testing.T) {
+	interceptor := CreateAuthorizationInterceptor(newTestAuthorizer(t))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	t.Run("Authorized", func(t *testing.T) {
+		ctx := contextWithClaims(&jwt.TokenClaims{Scopes: []string{"user:read"}})
+
+		response, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testOtherMethod}, handler)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "response", response)
+	})
+
+	t.Run("Public_Without_Claims", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: testPublicMethod}, handler)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Permission_Denied", func(t *testing.T) {
+		ctx := contextWithClaims(&jwt.TokenClaims{Scopes: []string{"user:read"}})
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testAdminMethod}, handler)
+
+		assertStatusCode(t, codes.PermissionDenied, err)
+	})
+}
+
+func TestAuthorizationStreamInterceptor(t *testing.T) {
+	interceptor := CreateAuthorizationStreamInterceptor(newTestAuthorizer(t))
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+
+	stream := &testServerStream{ctx: contextWithClaims(&jwt.TokenClaims{Scopes: []string{"user:read"}})}
+	assert.NoError(t, interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: testOtherMethod}, handler))
+
+	stream = &testServerStream{ctx: context.Background()}
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: testOtherMethod}, handler)
+	assertStatusCode(t, codes.Unauthenticated, err)
+}
+
+func TestGinAuthorizationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newRouter := func(claims *jwt.TokenClaims) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set(string(jwt.ClaimsContextKey), claims)
+		})
+		router.Use(CreateGinAuthorizationMiddleware(newTestAuthorizer(t)))
+		router.DELETE("/users/:id", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		return router
+	}
+	serve := func(router *gin.Engine) int {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/users/test-user-id", nil))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve(newRouter(&jwt.TokenClaims{Roles: []string{"admin"}})))
+	assert.Equal(t, http.StatusForbidden, serve(newRouter(&jwt.TokenClaims{Roles: []string{"user"}})))
+}
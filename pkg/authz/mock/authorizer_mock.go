@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: authorizer.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+// MockAuthorizerer is a mock of Authorizerer interface.
+type MockAuthorizerer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizererMockRecorder
+}
+
+// MockAuthorizererMockRecorder is the mock recorder for MockAuthorizerer.
+type MockAuthorizererMockRecorder struct {
+	mock *MockAuthorizerer
+}
+
+// NewMockAuthorizerer creates a new mock instance.
+func NewMockAuthorizerer(ctrl *gomock.Controller) *MockAuthorizerer {
+	mock := &MockAuthorizerer{ctrl: ctrl}
+	mock.recorder = &MockAuthorizererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizerer) EXPECT() *MockAuthorizererMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeMethod mocks base method.
+func (m *MockAuthorizerer) AuthorizeMethod(fullMethod string, claims *jwt.TokenClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeMethod", fullMethod, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeMethod indicates an expected call of AuthorizeMethod.
+func (mr *MockAuthorizererMockRecorder) AuthorizeMethod(fullMethod, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeMethod", reflect.TypeOf((*MockAuthorizerer)(nil).AuthorizeMethod), fullMethod, claims)
+}
+
+// AuthorizeRoute mocks base method.
+func (m *MockAuthorizerer) AuthorizeRoute(httpMethod, routePath string, claims *jwt.TokenClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeRoute", httpMethod, routePath, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeRoute indicates an expected call of AuthorizeRoute.
+func (mr *MockAuthorizererMockRecorder) AuthorizeRoute(httpMethod, routePath, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeRoute", reflect.TypeOf((*MockAuthorizerer)(nil).AuthorizeRoute), httpMethod, routePath, claims)
+}
@@ -0,0 +1,93 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+// PolicyConfigKey is the default configuration key of the authorization policy
+const PolicyConfigKey = "authorization"
+
+// Requirement is what the token claims need to be authorized
+type Requirement struct {
+	// Public requirements are satisfied without claims
+	Public bool `mapstructure:"public"`
+	// Scopes must all be granted
+	Scopes []string `mapstructure:"scopes"`
+	// Roles must include at least one of the listed roles
+	Roles []string `mapstructure:"roles"`
+}
+
+// Rule is the requirement of a gRPC full method name, e.g.
+// /pb_authentication.AuthenticationService/GetUserProfile, or of a Gin route
+// made of the HTTP method and the route path, e.g. GET /users/:id
+type Rule struct {
+	Method      string `mapstructure:"method"`
+	Route       string `mapstructure:"route"`
+	Requirement `mapstructure:",squash"`
+}
+
+// Policy is the list of rules of a service, methods and routes without a rule
+// need the default requirement
+type Policy struct {
+	Default Requirement `mapstructure:"default"`
+	Rules   []Rule      `mapstructure:"rules"`
+}
+
+// LoadPolicy loads the policy under the given key of the configuration, e.g.
+//
+//	authorization:
+//	  default:
+//	    scopes: [user:read]
+//	  rules:
+//	    - method: /pb_authentication.AuthenticationService/Authenticate
+//	      public: true
+//	    - route: DELETE /users/:id
+//	      roles: [admin]
+func LoadPolicy(config *viper.Viper, key string) (*Policy, error) {
+	var policy Policy
+	if err := config.UnmarshalKey(key, &policy); err != nil {
+		return nil, fmt.Errorf("Error unmarshaling authorization policy: %v", err)
+	}
+	return &policy, nil
+}
+
+// Check checks the claims satisfy the requirement, returning a PermissionDenied
+// status with the reason when they do not
+func (requirement Requirement) Check(claims *jwt.TokenClaims) error {
+	if requirement.Public {
+		return nil
+	}
+	if claims == nil {
+		return status.Error(codes.Unauthenticated, "Token claims not found")
+	}
+	for _, scope := range requirement.Scopes {
+		if !contains(claims.Scopes, scope) {
+			return status.Errorf(codes.PermissionDenied, "Scope %s is required", scope)
+		}
+	}
+	if len(requirement.Roles) == 0 {
+		return nil
+	}
+	for _, role := range requirement.Roles {
+		if contains(claims.Roles, role) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "One of the roles %s is required", strings.Join(requirement.Roles, ", "))
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -24,6 +24,7 @@ type Config struct {
 	AppName                   string  `mapstructure:"app_name"`
 	TLSEnabled                bool    `mapstructure:"tls_enabled"`
 	EmailVerificationEndpoint string  `mapstructure:"email_verification_endpoint"`
+	JWTAlgorithm              string  `mapstructure:"jwt_algorithm"`
 	GatewayService            Address `mapstructure:"gateway_service"`
 	EmailService              Address `mapstructure:"email_service"`
 	AuthenticationService     Address `mapstructure:"authentication_service"`
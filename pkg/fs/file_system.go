@@ -11,6 +11,10 @@ type FileSystem interface {
 	WriteFile(filename string, data []byte, perm os.FileMode) error
 	Stat(name string) (os.FileInfo, error)
 	Mkdir(name string, perm os.FileMode) error
+	ReadDir(name string) ([]os.DirEntry, error)
+	Remove(name string) error
+	Chmod(name string, mode os.FileMode) error
+	Rename(oldName, newName string) error
 	IsNotExist(err error) bool
 }
 
@@ -39,6 +43,26 @@ func (OSFileSystem) Mkdir(name string, perm os.FileMode) error {
 	return os.Mkdir(name, perm)
 }
 
+// ReadDir reads the entries of a directory
+func (OSFileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// Remove removes a file
+func (OSFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// Chmod changes the mode of a file
+func (OSFileSystem) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+// Rename renames a file, replacing the new one atomically when it exists
+func (OSFileSystem) Rename(oldName, newName string) error {
+	return os.Rename(oldName, newName)
+}
+
 // IsNotExist checks if an error is a not exist error
 func (OSFileSystem) IsNotExist(err error) bool {
 	return os.IsNotExist(err)
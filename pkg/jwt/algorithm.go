@@ -0,0 +1,167 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Algorithm is a JWT signing algorithm
+type Algorithm string
+
+// Supported signing algorithms
+const (
+	RS256 Algorithm = "RS256"
+	RS384 Algorithm = "RS384"
+	PS256 Algorithm = "PS256"
+	ES256 Algorithm = "ES256"
+	EdDSA Algorithm = "EdDSA"
+)
+
+// DefaultAlgorithm is the algorithm used when none is configured
+const DefaultAlgorithm = RS256
+
+const rsaKeySize = 2048
+
+// ParseAlgorithm parses a configured algorithm name, defaulting to RS256 when empty
+func ParseAlgorithm(name string) (Algorithm, error) {
+	if name == "" {
+		return DefaultAlgorithm, nil
+	}
+	algorithm := Algorithm(name)
+	switch algorithm {
+	case RS256, RS384, PS256, ES256, EdDSA:
+		return algorithm, nil
+	default:
+		return "", fmt.Errorf("Unsupported signing algorithm: %s", name)
+	}
+}
+
+// SigningMethod returns the JWT signing method of the algorithm
+func (algorithm Algorithm) SigningMethod() jwt.SigningMethod {
+	switch algorithm {
+	case RS384:
+		return jwt.SigningMethodRS384
+	case PS256:
+		return jwt.SigningMethodPS256
+	case ES256:
+		return jwt.SigningMethodES256
+	case EdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodRS256
+	}
+}
+
+// GenerateKey generates a new private key suitable for the algorithm
+func (algorithm Algorithm) GenerateKey() (crypto.Signer, error) {
+	switch algorithm {
+	case ES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case EdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	default:
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	}
+}
+
+// IsCompatibleWith checks whether the key can be used with the algorithm
+func (algorithm Algorithm) IsCompatibleWith(publicKey crypto.PublicKey) bool {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return algorithm == RS256 || algorithm == RS384 || algorithm == PS256
+	case *ecdsa.PublicKey:
+		return algorithm == ES256 && key.Curve == elliptic.P256()
+	case ed25519.PublicKey:
+		return algorithm == EdDSA
+	default:
+		return false
+	}
+}
+
+// AlgorithmForKey returns the preferred algorithm if the key supports it,
+// otherwise the default algorithm of the key type
+func AlgorithmForKey(publicKey crypto.PublicKey, preferred Algorithm) (Algorithm, error) {
+	if preferred.IsCompatibleWith(publicKey) {
+		return preferred, nil
+	}
+	for _, algorithm := range []Algorithm{RS256, ES256, EdDSA} {
+		if algorithm.IsCompatibleWith(publicKey) {
+			return algorithm, nil
+		}
+	}
+	return "", fmt.Errorf("Unsupported public key type: %T", publicKey)
+}
+
+// isSigningMethodCompatible checks the token signing method belongs to the key family
+func isSigningMethodCompatible(method jwt.SigningMethod, publicKey crypto.PublicKey) bool {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		switch method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodECDSA)
+		return ok
+	case ed25519.PublicKey:
+		_, ok := method.(*jwt.SigningMethodEd25519)
+		return ok
+	}
+	return false
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func encodeCoordinate(value *big.Int, curve elliptic.Curve) string {
+	size := (curve.Params().BitSize + 7) / 8
+	return base64.RawURLEncoding.EncodeToString(value.FillBytes(make([]byte, size)))
+}
+
+// KeyID returns the RFC 7638 JWK thumbprint of the public key, used as the "kid" header
+func KeyID(publicKey crypto.PublicKey) (string, error) {
+	var thumbprintInput []byte
+	var err error
+	// Members are marshalled in lexicographic order as required by RFC 7638
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		thumbprintInput, err = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{encodeBigInt(big.NewInt(int64(key.E))), "RSA", encodeBigInt(key.N)})
+	case *ecdsa.PublicKey:
+		thumbprintInput, err = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{key.Curve.Params().Name, "EC", encodeCoordinate(key.X, key.Curve), encodeCoordinate(key.Y, key.Curve)})
+	case ed25519.PublicKey:
+		thumbprintInput, err = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{"Ed25519", "OKP", base64.RawURLEncoding.EncodeToString(key)})
+	default:
+		return "", fmt.Errorf("Unsupported public key type: %T", publicKey)
+	}
+	if err != nil {
+		return "", err
+	}
+	thumbprint := sha256.Sum256(thumbprintInput)
+	return base64.RawURLEncoding.EncodeToString(thumbprint[:]), nil
+}
@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/config"
+)
+
+func TestSigningAlgorithms(t *testing.T) {
+	for _, algorithm := range []Algorithm{RS256, RS384, PS256, ES256, EdDSA} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			keyLocation := t.TempDir()
+			keyManager, err := NewKeyManager(keyLocation, WithAlgorithm(algorithm))
+			assert.NoError(t, err)
+			tokenString := signTestToken(t, NewTokenSignerFromKeyManager(keyManager))
+
+			jwtToken, err := newVerifierFromKeyManager(t, keyManager).Verify(tokenString)
+			assert.NoError(t, err)
+			assert.Equal(t, string(algorithm), jwtToken.Method.Alg())
+
+			keySet, err := keyManager.GetJSONWebKeySet()
+			assert.NoError(t, err)
+			publicKeys, err := keySet.PublicKeys()
+			assert.NoError(t, err)
+			_, err = NewTokenVerifierFromKeys(publicKeys).Verify(tokenString)
+			assert.NoError(t, err)
+			assert.Equal(t, string(algorithm), keySet.Keys[0].Algorithm)
+
+			reloadedKeyManager, err := NewKeyManager(keyLocation, WithAlgorithm(algorithm))
+			assert.NoError(t, err)
+			assert.Equal(t, keyManager.GetKeyID(), reloadedKeyManager.GetKeyID())
+		})
+	}
+}
+
+func TestAlgorithmChangeRotatesKey(t *testing.T) {
+	keyLocation := t.TempDir()
+	keyManager, err := NewKeyManager(keyLocation)
+	assert.NoError(t, err)
+	rsaToken := signTestToken(t, NewTokenSignerFromKeyManager(keyManager))
+
+	ecdsaKeyManager, err := NewKeyManager(keyLocation, WithAlgorithm(ES256))
+	assert.NoError(t, err)
+	assert.NotEqual(t, keyManager.GetKeyID(), ecdsaKeyManager.GetKeyID())
+	assert.Nil(t, ecdsaKeyManager.GetRSAPrivateKey())
+	ecdsaToken := signTestToken(t, NewTokenSignerFromKeyManager(ecdsaKeyManager))
+
+	verifier := newVerifierFromKeyManager(t, ecdsaKeyManager)
+	_, err = verifier.Verify(rsaToken)
+	assert.NoError(t, err)
+	_, err = verifier.Verify(ecdsaToken)
+	assert.NoError(t, err)
+}
+
+func TestSigningAlgorithmMismatch(t *testing.T) {
+	keyManager, err := NewKeyManager(t.TempDir(), WithAlgorithm(EdDSA))
+	assert.NoError(t, err)
+	signer := NewTokenSigner(keyManager.GetPrivateKey(), WithSigningAlgorithm(RS256))
+
+	_, err = signer.SignToken()
+	assert.Error(t, err)
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	algorithm, err := ParseAlgorithm("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultAlgorithm, algorithm)
+
+	algorithm, err = ParseAlgorithm("ES256")
+	assert.NoError(t, err)
+	assert.Equal(t, ES256, algorithm)
+
+	_, err = ParseAlgorithm("HS256")
+	assert.Error(t, err)
+}
+
+func TestKeyManagerFromConfig(t *testing.T) {
+	keyManager, err := NewKeyManagerFromConfig("", &config.Config{JWTAlgorithm: "ES256"}, WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	assert.Equal(t, ES256, keyManager.GetAlgorithm())
+
+	keyManager, err = NewKeyManagerFromConfig("", &config.Config{}, WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultAlgorithm, keyManager.GetAlgorithm())
+
+	_, err = NewKeyManagerFromConfig("", &config.Config{JWTAlgorithm: "HS256"}, WithKeyStore(NewMemoryKeyStore()))
+	assert.Error(t, err)
+}
+
+func TestTokenSignerWithoutPrivateKey(t *testing.T) {
+	_, err := NewTokenSigner(nil).SignToken()
+	assert.Error(t, err)
+
+	var privateKey *rsa.PrivateKey
+	_, err = NewTokenSigner(privateKey).SignToken()
+	assert.Error(t, err)
+}
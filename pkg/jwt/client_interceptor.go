@@ -0,0 +1,122 @@
+package jwt
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func hasOutgoingAuthorization(ctx context.Context) bool {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	return ok && len(md.Get(AuthorizationMetadataKey)) > 0
+}
+
+// retryToken invalidates the rejected token and gets a new one, empty when the
+// token source cannot provide a different token
+func retryToken(ctx context.Context, tokenSource TokenSource, rejectedToken string) string {
+	tokenSource.Invalidate(rejectedToken)
+	tokenString, err := tokenSource.Token(ctx)
+	if err != nil || tokenString == rejectedToken {
+		return ""
+	}
+	return tokenString
+}
+
+// CreateAuthenticationClientInterceptor is the client interceptor that adds the Bearer token of the
+// token source to the outgoing gRPC calls. A call rejected as Unauthenticated is retried once with
+// a new token. Calls whose context already holds an authorization are sent unchanged, e.g. the
+// RefreshToken calls made by a RefreshingTokenSource.
+func CreateAuthenticationClientInterceptor(tokenSource TokenSource) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if hasOutgoingAuthorization(ctx) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		tokenString, err := tokenSource.Token(ctx)
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "Failed to get token: %v", err)
+		}
+		err = invoker(AddAuthorizationMetadataToContext(ctx, tokenString), method, req, reply, cc, opts...)
+		if status.Code(err) != codes.Unauthenticated {
+			return err
+		}
+		newTokenString := retryToken(ctx, tokenSource, tokenString)
+		if newTokenString == "" {
+			return err
+		}
+		return invoker(AddAuthorizationMetadataToContext(ctx, newTokenString), method, req, reply, cc, opts...)
+	}
+}
+
+// authenticatedClientStream invalidates the token of the stream once the stream is rejected
+// as Unauthenticated, which happens on the first Header or RecvMsg call rather than when the
+// stream is opened
+type authenticatedClientStream struct {
+	grpc.ClientStream
+	tokenSource TokenSource
+	tokenString string
+}
+
+func (stream *authenticatedClientStream) invalidateRejectedToken(err error) {
+	if status.Code(err) == codes.Unauthenticated {
+		stream.tokenSource.Invalidate(stream.tokenString)
+	}
+}
+
+// Header gets the header metadata, invalidating the token when the stream was rejected
+func (stream *authenticatedClientStream) Header() (metadata.MD, error) {
+	md, err := stream.ClientStream.Header()
+	stream.invalidateRejectedToken(err)
+	return md, err
+}
+
+// RecvMsg receives a message, invalidating the token when the stream was rejected
+func (stream *authenticatedClientStream) RecvMsg(message interface{}) error {
+	err := stream.ClientStream.RecvMsg(message)
+	stream.invalidateRejectedToken(err)
+	return err
+}
+
+// CreateAuthenticationClientStreamInterceptor is the client interceptor that adds the Bearer token
+// of the token source to the outgoing streaming gRPC calls. Unlike unary calls, streams rejected
+// as Unauthenticated are not retried as their messages cannot be replayed, the rejected token is
+// invalidated so the next call gets a new one.
+func CreateAuthenticationClientStreamInterceptor(tokenSource TokenSource) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if hasOutgoingAuthorization(ctx) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		tokenString, err := tokenSource.Token(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "Failed to get token: %v", err)
+		}
+		stream, err := streamer(AddAuthorizationMetadataToContext(ctx, tokenString), desc, cc, method, opts...)
+		if err != nil {
+			if status.Code(err) == codes.Unauthenticated {
+				tokenSource.Invalidate(tokenString)
+			}
+			return nil, err
+		}
+		return &authenticatedClientStream{
+			ClientStream: stream,
+			tokenSource:  tokenSource,
+			tokenString:  tokenString,
+		}, nil
+	}
+}
@@ -0,0 +1,306 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/quadev-ltd/qd-common/pb/gen/go/pb_authentication"
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+func (server *testAuthenticationServer) RefreshToken(
+	ctx context.Context,
+	_ *pb_authentication.RefreshTokenRequest,
+) (*pb_authentication.AuthenticateResponse, error) {
+	refreshToken, err := GetBearerTokenFromIncomingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := verifyToken(ctx, refreshToken, NewTokenVerifierFromKeyManager(server.keyManager), &TokenInspector{})
+	if err != nil {
+		return nil, err
+	}
+	if err := (MethodRule{TokenType: token.RefreshTokenType}).CheckClaims(claims); err != nil {
+		return nil, err
+	}
+	issuer := NewTokenIssuer(NewTokenSignerFromKeyManager(server.keyManager))
+	authToken, err := issuer.IssueAuthToken(claims.Email, claims.UserID, claims.HasPaidFeatures)
+	if err != nil {
+		return nil, err
+	}
+	newRefreshToken, err := issuer.IssueRefreshToken(claims.Email, claims.UserID, claims.HasPaidFeatures)
+	if err != nil {
+		return nil, err
+	}
+	return &pb_authentication.AuthenticateResponse{
+		AuthToken:    authToken.Token,
+		RefreshToken: newRefreshToken.Token,
+	}, nil
+}
+
+// recordingInvoker records the authorization of the calls, rejecting the tokens not accepted
+type recordingInvoker struct {
+	authorizations []string
+	accept         func(authorization string) bool
+}
+
+func (invoker *recordingInvoker) invoke(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	opts ...grpc.CallOption,
+) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	authorization := md.Get(AuthorizationMetadataKey)
+	if len(authorization) != 1 {
+		return status.Error(codes.Unauthenticated, "Authorization token not found in metadata")
+	}
+	invoker.authorizations = append(invoker.authorizations, authorization[0])
+	if !invoker.accept(authorization[0]) {
+		return ToStatusError(ErrTokenExpired)
+	}
+	return nil
+}
+
+func TestAuthenticationClientInterceptor(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	signer := NewTokenSignerFromKeyManager(keyManager)
+	acceptAll := func(string) bool { return true }
+
+	t.Run("Adds_Bearer_Token", func(t *testing.T) {
+		invoker := &recordingInvoker{accept: acceptAll}
+		interceptor := CreateAuthenticationClientInterceptor(NewStaticTokenSource("test-token"))
+
+		err := interceptor(context.Background(), testMethod, "request", "reply", nil, invoker.invoke)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{BearerPrefix + "test-token"}, invoker.authorizations)
+	})
+
+	t.Run("Keeps_Existing_Authorization", func(t *testing.T) {
+		invoker := &recordingInvoker{accept: acceptAll}
+		interceptor := CreateAuthenticationClientInterceptor(NewStaticTokenSource("test-token"))
+		ctx := AddAuthorizationMetadataToContext(context.Background(), "refresh-token")
+
+		err := interceptor(ctx, testMethod, "request", "reply", nil, invoker.invoke)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{BearerPrefix + "refresh-token"}, invoker.authorizations)
+	})
+
+	t.Run("Retries_Once_With_New_Token", func(t *testing.T) {
+		tokenSource := NewSignedTokenSource(signer, time.Hour, []ClaimPair{{TypeClaim, token.AuthTokenType}})
+		rejectedToken, err := tokenSource.Token(context.Background())
+		assert.NoError(t, err)
+		invoker := &recordingInvoker{accept: func(authorization string) bool {
+			return authorization != BearerPrefix+rejectedToken
+		}}
+		interceptor := CreateAuthenticationClientInterceptor(tokenSource)
+
+		err = interceptor(context.Background(), testMethod, "request", "reply", nil, invoker.invoke)
+
+		assert.NoError(t, err)
+		assert.Len(t, invoker.authorizations, 2)
+		assert.NotEqual(t, invoker.authorizations[0], invoker.authorizations[1])
+	})
+
+	t.Run("Does_Not_Retry_Same_Token", func(t *testing.T) {
+		invoker := &recordingInvoker{accept: func(string) bool { return false }}
+		interceptor := CreateAuthenticationClientInterceptor(NewStaticTokenSource("test-token"))
+
+		err := interceptor(context.Background(), testMethod, "request", "reply", nil, invoker.invoke)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Len(t, invoker.authorizations, 1)
+	})
+
+	t.Run("Stream_Adds_Bearer_Token", func(t *testing.T) {
+		var authorization []string
+		streamer := func(
+			ctx context.Context,
+			desc *grpc.StreamDesc,
+			cc *grpc.ClientConn,
+			method string,
+			opts ...grpc.CallOption,
+		) (grpc.ClientStream, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			authorization = md.Get(AuthorizationMetadataKey)
+			return nil, nil
+		}
+		interceptor := CreateAuthenticationClientStreamInterceptor(NewStaticTokenSource("test-token"))
+
+		_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, testMethod, streamer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{BearerPrefix + "test-token"}, authorization)
+	})
+}
+
+// invalidatingTokenSource provides its tokens in order, moving to the next one when the current one is invalidated
+type invalidatingTokenSource struct {
+	mutex       sync.Mutex
+	tokens      []string
+	invalidated []string
+}
+
+func (source *invalidatingTokenSource) Token(ctx context.Context) (string, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return source.tokens[len(source.invalidated)], nil
+}
+
+func (source *invalidatingTokenSource) Invalidate(token string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.invalidated = append(source.invalidated, token)
+}
+
+func newBufconnAuthenticatedHealthClient(t *testing.T, keyManager KeyManagerer, tokenSource TokenSource) grpc_health_v1.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.StreamInterceptor(
+		CreateAuthenticationStreamInterceptor(NewTokenVerifierFromKeyManager(keyManager), &TokenInspector{}, nil),
+	))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(CreateAuthenticationClientStreamInterceptor(tokenSource)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { connection.Close() })
+	return grpc_health_v1.NewHealthClient(connection)
+}
+
+func TestAuthenticationClientStreamInterceptorRejectedStream(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	authToken, err := NewTokenIssuer(NewTokenSignerFromKeyManager(keyManager)).IssueAuthToken("test@email.com", "test-user-id", false)
+	assert.NoError(t, err)
+	tokenSource := &invalidatingTokenSource{tokens: []string{"rejected-token", authToken.Token}}
+	client := newBufconnAuthenticatedHealthClient(t, keyManager, tokenSource)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The stream opens without error, the rejection comes with the first message
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, []string{"rejected-token"}, tokenSource.invalidated)
+
+	stream, err = client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	response, err := stream.Recv()
+	if assert.NoError(t, err) {
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+	}
+	assert.Equal(t, []string{"rejected-token"}, tokenSource.invalidated)
+}
+
+func TestSignedTokenSource(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	signer := NewTokenSignerFromKeyManager(keyManager)
+	verifier := NewTokenVerifierFromKeyManager(keyManager)
+
+	t.Run("Caches_Token", func(t *testing.T) {
+		tokenSource := NewSignedTokenSource(signer, time.Hour, []ClaimPair{{TypeClaim, token.AuthTokenType}})
+
+		first, err := tokenSource.Token(context.Background())
+		assert.NoError(t, err)
+		second, err := tokenSource.Token(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+		_, err = verifier.Verify(first)
+		assert.NoError(t, err)
+
+		tokenSource.Invalidate(first)
+		third, err := tokenSource.Token(context.Background())
+		assert.NoError(t, err)
+		assert.NotEqual(t, first, third)
+	})
+
+	t.Run("Refreshes_Proactively", func(t *testing.T) {
+		tokenSource := NewSignedTokenSource(signer, time.Minute, nil, WithRefreshWindow(2*time.Minute))
+
+		first, err := tokenSource.Token(context.Background())
+		assert.NoError(t, err)
+		second, err := tokenSource.Token(context.Background())
+		assert.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestRefreshingTokenSource(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	client := newBufconnAuthenticationClient(t, keyManager)
+	issuer := NewTokenIssuer(NewTokenSignerFromKeyManager(keyManager))
+	verifier := NewTokenVerifierFromKeyManager(keyManager)
+
+	t.Run("Refreshes_Expiring_Token", func(t *testing.T) {
+		expiringToken, err := NewTokenIssuer(
+			NewTokenSignerFromKeyManager(keyManager),
+			WithTokenLifetime(token.AuthTokenType, 10*time.Second),
+		).IssueAuthToken("test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+		refreshToken, err := issuer.IssueRefreshToken("test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+		var refreshedTokens []string
+		tokenSource, err := NewRefreshingTokenSource(
+			client,
+			expiringToken.Token,
+			refreshToken.Token,
+			func(authToken, refreshToken string) {
+				refreshedTokens = []string{authToken, refreshToken}
+			},
+		)
+		assert.NoError(t, err)
+
+		authToken, err := tokenSource.Token(context.Background())
+		assert.NoError(t, err)
+		assert.NotEqual(t, expiringToken.Token, authToken)
+		assert.Equal(t, authToken, refreshedTokens[0])
+		assert.NotEqual(t, refreshToken.Token, refreshedTokens[1])
+		jwtToken, err := verifier.Verify(authToken)
+		assert.NoError(t, err)
+		tokenType, err := (&TokenInspector{}).GetTypeFromToken(jwtToken)
+		assert.NoError(t, err)
+		assert.Equal(t, token.AuthTokenType, *tokenType)
+	})
+
+	t.Run("Refresh_Rejected", func(t *testing.T) {
+		authToken, err := issuer.IssueAuthToken("test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+		tokenSource, err := NewRefreshingTokenSource(client, "", authToken.Token, nil)
+		assert.NoError(t, err)
+
+		_, err = tokenSource.Token(context.Background())
+		assert.True(t, errors.Is(err, ErrWrongTokenType))
+	})
+}
@@ -1,15 +1,36 @@
 package jwt
 
+import "time"
+
 // Key constants
 const (
-	EmailClaim           = "email"
-	ExpiryClaim          = "exp"
-	IssuedAtClaim        = "iat"
-	TypeClaim            = "type"
-	UserIDClaim          = "userID"
-	HasPaidFeaturesClaim = "hasPaidFeatures"
-	PublicKeyFileName    = "public.pem"
-	PrivateKeyFileName   = "private.pem"
-	PublicKeyType        = "RSA PUBLIC KEY"
-	PrivateKeyType       = "RSA PRIVATE KEY"
+	EmailClaim                     = "email"
+	ExpiryClaim                    = "exp"
+	IssuedAtClaim                  = "iat"
+	TypeClaim                      = "type"
+	UserIDClaim                    = "userID"
+	HasPaidFeaturesClaim           = "hasPaidFeatures"
+	IssuerClaim                    = "iss"
+	AudienceClaim                  = "aud"
+	SubjectClaim                   = "sub"
+	NotBeforeClaim                 = "nbf"
+	JWTIDClaim                     = "jti"
+	FamilyIDClaim                  = "fid"
+	RolesClaim                     = "roles"
+	ScopesClaim                    = "scopes"
+	KeyIDHeader                    = "kid"
+	PublicKeyFileName              = "public.pem"
+	PrivateKeyFileName             = "private.pem"
+	RetiredPublicKeyFileNameFormat = "public.%s.pem"
+	RetiredPublicKeyFileNamePrefix = "public."
+	RetiredPublicKeyFileNameSuffix = ".pem"
+	ExpiresAtPEMHeader             = "Expires-At"
+	PublicKeyType                  = "RSA PUBLIC KEY"
+	PrivateKeyType                 = "RSA PRIVATE KEY"
+	PKIXPublicKeyType              = "PUBLIC KEY"
+	PKCS8PrivateKeyType            = "PRIVATE KEY"
+	ECPrivateKeyType               = "EC PRIVATE KEY"
 )
+
+// DefaultKeyGracePeriod is how long a rotated key keeps verifying tokens by default
+const DefaultKeyGracePeriod = 24 * time.Hour
@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// GetCustomClaims decodes the claims of a JWT token into T, mapping the claims to fields
+// by their json tags, e.g. struct { Roles []string `json:"roles"` }
+func GetCustomClaims[T any](jwtToken *jwt.Token) (*T, error) {
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("%w: claims are not a JSON object", ErrTokenMalformed)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return nil, fmt.Errorf("Error encoding claims: %v", err)
+	}
+	var customClaims T
+	if err := json.Unmarshal(claimsJSON, &customClaims); err != nil {
+		return nil, fmt.Errorf("Error decoding custom claims: %v", err)
+	}
+	return &customClaims, nil
+}
+
+// GetCustomClaim decodes a single claim of a JWT token into T, nil if the claim is not present
+func GetCustomClaim[T any](jwtToken *jwt.Token, claimKey string) (*T, error) {
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("%w: claims are not a JSON object", ErrTokenMalformed)
+	}
+	value, exists := claims[claimKey]
+	if !exists {
+		return nil, nil
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("Error encoding claim %s: %v", claimKey, err)
+	}
+	var customClaim T
+	if err := json.Unmarshal(valueJSON, &customClaim); err != nil {
+		return nil, fmt.Errorf("%w: %v", &InvalidClaimError{Name: claimKey}, err)
+	}
+	return &customClaim, nil
+}
+
+// GetCustomClaimsFromTokenString decodes the claims of a JWT token string into T without verifying it
+func GetCustomClaimsFromTokenString[T any](tokenStr string) (*T, error) {
+	token, _, err := new(jwt.Parser).ParseUnverified(tokenStr, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+	return GetCustomClaims[T](token)
+}
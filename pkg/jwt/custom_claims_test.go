@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+type testPreferences struct {
+	Locale   string `json:"locale"`
+	Timezone string `json:"timezone"`
+}
+
+type testCustomClaims struct {
+	UserID      string            `json:"userID"`
+	Type        token.Type        `json:"type"`
+	Expiry      int64             `json:"exp"`
+	TenantID    string            `json:"tenantID"`
+	Roles       []string          `json:"roles"`
+	Quota       int               `json:"quota"`
+	Preferences testPreferences   `json:"preferences"`
+	Limits      map[string]int    `json:"limits"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+func TestCustomClaims(t *testing.T) {
+	keyManager, err := NewKeyManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewTokenSignerFromKeyManager(keyManager)
+	verifier := newVerifierFromKeyManager(t, keyManager)
+	expiry := time.Now().Add(time.Hour)
+	preferences := testPreferences{Locale: "en-GB", Timezone: "Europe/London"}
+
+	tokenString, err := signer.SignToken(
+		ClaimPair{ExpiryClaim, expiry},
+		ClaimPair{TypeClaim, token.AuthTokenType},
+		ClaimPair{UserIDClaim, "test-user-id"},
+		ClaimPair{"tenantID", "test-tenant-id"},
+		ClaimPair{"roles", []string{"admin", "editor"}},
+		ClaimPair{"quota", 42},
+		ClaimPair{"preferences", preferences},
+		ClaimPair{"limits", map[string]int{"images": 10}},
+	)
+	assert.NoError(t, err)
+	jwtToken, err := verifier.Verify(*tokenString)
+	assert.NoError(t, err)
+
+	t.Run("Get_Custom_Claims", func(t *testing.T) {
+		claims, err := GetCustomClaims[testCustomClaims](jwtToken)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &testCustomClaims{
+			UserID:      "test-user-id",
+			Type:        token.AuthTokenType,
+			Expiry:      expiry.Unix(),
+			TenantID:    "test-tenant-id",
+			Roles:       []string{"admin", "editor"},
+			Quota:       42,
+			Preferences: preferences,
+			Limits:      map[string]int{"images": 10},
+		}, claims)
+	})
+
+	t.Run("Get_Custom_Claim", func(t *testing.T) {
+		roles, err := GetCustomClaim[[]string](jwtToken, "roles")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"admin", "editor"}, *roles)
+
+		claimPreferences, err := GetCustomClaim[testPreferences](jwtToken, "preferences")
+		assert.NoError(t, err)
+		assert.Equal(t, preferences, *claimPreferences)
+
+		missing, err := GetCustomClaim[string](jwtToken, "locale")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+
+		_, err = GetCustomClaim[int](jwtToken, "roles")
+		assert.Error(t, err)
+	})
+
+	t.Run("Get_Custom_Claims_From_Token_String", func(t *testing.T) {
+		claims, err := GetCustomClaimsFromTokenString[testCustomClaims](*tokenString)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test-tenant-id", claims.TenantID)
+	})
+
+	t.Run("Claims_Must_Be_JSON_Serialisable", func(t *testing.T) {
+		_, err := signer.SignToken(ClaimPair{"callback", func() {}})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,215 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details of the token status errors
+const ErrorDomain = "jwt.qd-common"
+
+// Error reasons of the ErrorInfo details of the token status errors, clients refresh the
+// token on ReasonTokenExpired and ask the user to log in again on the other reasons
+const (
+	ReasonTokenMalformed      = "TOKEN_MALFORMED"
+	ReasonSignatureInvalid    = "SIGNATURE_INVALID"
+	ReasonTokenExpired        = "TOKEN_EXPIRED"
+	ReasonTokenNotValidYet    = "TOKEN_NOT_VALID_YET"
+	ReasonTokenIssuedInFuture = "TOKEN_ISSUED_IN_FUTURE"
+	ReasonInvalidIssuer       = "INVALID_ISSUER"
+	ReasonInvalidAudience     = "INVALID_AUDIENCE"
+	ReasonTokenRevoked        = "TOKEN_REVOKED"
+	ReasonTokenInactive       = "TOKEN_INACTIVE"
+	ReasonTokenConsumed       = "TOKEN_CONSUMED"
+	ReasonWrongTokenType      = "WRONG_TOKEN_TYPE"
+	ReasonMissingClaim        = "MISSING_CLAIM"
+	ReasonInvalidClaim        = "INVALID_CLAIM"
+	// ReasonVerificationUnavailable is the reason of the failures of the dependencies of the
+	// verification, e.g. the key source or the revocation store, clients may retry the call
+	ReasonVerificationUnavailable = "VERIFICATION_UNAVAILABLE"
+	// ClaimMetadataKey is the ErrorInfo metadata key of the missing or invalid claim name
+	ClaimMetadataKey = "claim"
+)
+
+type errorReason struct {
+	err    error
+	reason string
+	code   codes.Code
+}
+
+// errorReasons is ordered so the claim errors match before ErrTokenMalformed
+var errorReasons = []errorReason{
+	{ErrMissingClaim, ReasonMissingClaim, codes.Unauthenticated},
+	{ErrInvalidClaim, ReasonInvalidClaim, codes.Unauthenticated},
+	{ErrTokenMalformed, ReasonTokenMalformed, codes.Unauthenticated},
+	{ErrSignatureInvalid, ReasonSignatureInvalid, codes.Unauthenticated},
+	{ErrTokenExpired, ReasonTokenExpired, codes.Unauthenticated},
+	{ErrTokenNotValidYet, ReasonTokenNotValidYet, codes.Unauthenticated},
+	{ErrTokenIssuedInFuture, ReasonTokenIssuedInFuture, codes.Unauthenticated},
+	{ErrInvalidIssuer, ReasonInvalidIssuer, codes.Unauthenticated},
+	{ErrInvalidAudience, ReasonInvalidAudience, codes.Unauthenticated},
+	{ErrTokenRevoked, ReasonTokenRevoked, codes.Unauthenticated},
+	{ErrTokenInactive, ReasonTokenInactive, codes.Unauthenticated},
+	{ErrTokenConsumed, ReasonTokenConsumed, codes.Unauthenticated},
+	{ErrWrongTokenType, ReasonWrongTokenType, codes.PermissionDenied},
+}
+
+// isVerificationError checks the error is one of the token verification errors, as opposed to
+// a failure of a dependency of the verification, e.g. the revocation store
+func isVerificationError(err error) bool {
+	for _, errorReason := range errorReasons {
+		if errors.Is(err, errorReason.err) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerificationUnavailableMessage is the status message of the errors which are not verification
+// errors, their details are not sent to the clients
+const VerificationUnavailableMessage = "Token verification is unavailable"
+
+// ToStatusError converts a token verification error into a gRPC status error with an
+// ErrorInfo detail holding the reason. Status errors are returned unchanged and any other
+// error, a failure of a dependency of the verification, is Unavailable with
+// ReasonVerificationUnavailable and a generic message.
+func ToStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if !isVerificationError(err) {
+		return withErrorInfo(status.New(codes.Unavailable, VerificationUnavailableMessage), &errdetails.ErrorInfo{
+			Reason: ReasonVerificationUnavailable,
+			Domain: ErrorDomain,
+		})
+	}
+	errorInfo := &errdetails.ErrorInfo{
+		Domain: ErrorDomain,
+	}
+	code := codes.Unauthenticated
+	for _, errorReason := range errorReasons {
+		if errors.Is(err, errorReason.err) {
+			errorInfo.Reason = errorReason.reason
+			code = errorReason.code
+			break
+		}
+	}
+	var missingClaimErr *MissingClaimError
+	var invalidClaimErr *InvalidClaimError
+	if errors.As(err, &missingClaimErr) {
+		errorInfo.Metadata = map[string]string{ClaimMetadataKey: missingClaimErr.Name}
+	} else if errors.As(err, &invalidClaimErr) {
+		errorInfo.Metadata = map[string]string{ClaimMetadataKey: invalidClaimErr.Name}
+	}
+	return withErrorInfo(status.New(code, err.Error()), errorInfo)
+}
+
+func withErrorInfo(statusError *status.Status, errorInfo *errdetails.ErrorInfo) error {
+	detailedStatusError, detailErr := statusError.WithDetails(errorInfo)
+	if detailErr != nil {
+		return statusError.Err()
+	}
+	return detailedStatusError.Err()
+}
+
+// logUnavailableError logs the errors whose details ToStatusError hides from the clients,
+// with the logger of the context or the logger of the package when there is none
+func logUnavailableError(ctx context.Context, err error) {
+	if err == nil || isVerificationError(err) {
+		return
+	}
+	if _, ok := status.FromError(err); ok {
+		return
+	}
+	getLogger(ctx).Error(err, "Failed to verify token")
+}
+
+// toLoggedStatusError converts the error with ToStatusError, logging the details it hides
+func toLoggedStatusError(ctx context.Context, err error) error {
+	logUnavailableError(ctx, err)
+	return ToStatusError(err)
+}
+
+func getErrorInfo(statusError *status.Status) *errdetails.ErrorInfo {
+	for _, detail := range statusError.Details() {
+		if errorInfo, ok := detail.(*errdetails.ErrorInfo); ok && errorInfo.Domain == ErrorDomain {
+			return errorInfo
+		}
+	}
+	return nil
+}
+
+// GetErrorReason gets the reason of a token status error, empty when the error has no token ErrorInfo
+func GetErrorReason(err error) string {
+	errorInfo := getErrorInfo(status.Convert(err))
+	if errorInfo == nil {
+		return ""
+	}
+	return errorInfo.Reason
+}
+
+// ErrorFromStatus converts a token status error received by a client back into an error
+// matching the verification error with errors.Is and errors.As, the status is kept
+func ErrorFromStatus(err error) error {
+	errorInfo := getErrorInfo(status.Convert(err))
+	if errorInfo == nil {
+		return err
+	}
+	switch errorInfo.Reason {
+	case ReasonMissingClaim:
+		return fmt.Errorf("%w: %w", &MissingClaimError{Name: errorInfo.Metadata[ClaimMetadataKey]}, err)
+	case ReasonInvalidClaim:
+		return fmt.Errorf("%w: %w", &InvalidClaimError{Name: errorInfo.Metadata[ClaimMetadataKey]}, err)
+	}
+	for _, errorReason := range errorReasons {
+		if errorReason.reason == errorInfo.Reason {
+			return fmt.Errorf("%w: %w", errorReason.err, err)
+		}
+	}
+	return err
+}
+
+// HTTPStatusFromCode gets the HTTP status matching the gRPC code
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// HTTPStatusFromError gets the HTTP status of a token verification or status error,
+// 401 for the authentication errors and 403 for ErrWrongTokenType and PermissionDenied
+func HTTPStatusFromError(err error) int {
+	return HTTPStatusFromCode(status.Code(ToStatusError(err)))
+}
@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/log"
+)
+
+func TestToStatusError(t *testing.T) {
+	testCases := map[string]struct {
+		err            error
+		expectedCode   codes.Code
+		expectedReason string
+		expectedHTTP   int
+	}{
+		"Expired": {
+			err:            ErrTokenExpired,
+			expectedCode:   codes.Unauthenticated,
+			expectedReason: ReasonTokenExpired,
+			expectedHTTP:   http.StatusUnauthorized,
+		},
+		"Wrapped_Signature_Invalid": {
+			err:            fmt.Errorf("%w: crypto/rsa: verification error", ErrSignatureInvalid),
+			expectedCode:   codes.Unauthenticated,
+			expectedReason: ReasonSignatureInvalid,
+			expectedHTTP:   http.StatusUnauthorized,
+		},
+		"Invalid_Claim": {
+			err:            &InvalidClaimError{Name: EmailClaim},
+			expectedCode:   codes.Unauthenticated,
+			expectedReason: ReasonInvalidClaim,
+			expectedHTTP:   http.StatusUnauthorized,
+		},
+		"Wrong_Token_Type": {
+			err:            ErrWrongTokenType,
+			expectedCode:   codes.PermissionDenied,
+			expectedReason: ReasonWrongTokenType,
+			expectedHTTP:   http.StatusForbidden,
+		},
+		"Unavailable_Dependency": {
+			err:            errors.New("Token Verifier: Failed to check token revocation: dial tcp 10.0.0.1:6379"),
+			expectedCode:   codes.Unavailable,
+			expectedReason: ReasonVerificationUnavailable,
+			expectedHTTP:   http.StatusServiceUnavailable,
+		},
+		"Keys_Unavailable": {
+			err:            fmt.Errorf("%w: Failed to fetch public keys: timeout", ErrKeysUnavailable),
+			expectedCode:   codes.Unavailable,
+			expectedReason: ReasonVerificationUnavailable,
+			expectedHTTP:   http.StatusServiceUnavailable,
+		},
+		"Status_Error": {
+			err:            status.Error(codes.Unavailable, "Key source is unavailable"),
+			expectedCode:   codes.Unavailable,
+			expectedReason: "",
+			expectedHTTP:   http.StatusServiceUnavailable,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			statusErr := ToStatusError(testCase.err)
+
+			assert.Equal(t, testCase.expectedCode, status.Code(statusErr))
+			assert.Equal(t, testCase.expectedReason, GetErrorReason(statusErr))
+			assert.Equal(t, testCase.expectedHTTP, HTTPStatusFromError(testCase.err))
+		})
+	}
+	assert.NoError(t, ToStatusError(nil))
+
+	t.Run("Hides_Unavailable_Error_Details", func(t *testing.T) {
+		statusErr := ToStatusError(errors.New("Failed to fetch http://10.0.0.1/keys"))
+
+		assert.Equal(t, VerificationUnavailableMessage, status.Convert(statusErr).Message())
+	})
+
+	t.Run("Logs_Unavailable_Error_With_Package_Logger", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		SetLogger(log.NewLogFactory("development", log.WithOutput(buffer)).NewPackageLogger(LoggerPackageName))
+		defer SetLogger(nil)
+
+		toLoggedStatusError(context.Background(), errors.New("Failed to fetch http://10.0.0.1/keys"))
+		toLoggedStatusError(context.Background(), ErrTokenExpired)
+
+		assert.Equal(t, 1, bytes.Count(buffer.Bytes(), []byte("Failed to verify token")))
+		assert.Contains(t, buffer.String(), "http://10.0.0.1/keys")
+	})
+}
+
+func TestErrorFromStatus(t *testing.T) {
+	t.Run("Sentinel_Error", func(t *testing.T) {
+		err := ErrorFromStatus(ToStatusError(fmt.Errorf("%w: expired 1 minute ago", ErrTokenExpired)))
+
+		assert.True(t, errors.Is(err, ErrTokenExpired))
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Missing_Claim", func(t *testing.T) {
+		err := ErrorFromStatus(ToStatusError(&MissingClaimError{Name: UserIDClaim}))
+
+		var missingClaimErr *MissingClaimError
+		assert.True(t, errors.As(err, &missingClaimErr))
+		assert.Equal(t, UserIDClaim, missingClaimErr.Name)
+	})
+
+	t.Run("Other_Status_Error", func(t *testing.T) {
+		statusErr := status.Error(codes.NotFound, "User not found")
+
+		assert.Equal(t, statusErr, ErrorFromStatus(statusErr))
+	})
+}
@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Token verification errors
+var (
+	ErrTokenMalformed      = errors.New("Token Verifier: JWT Token is malformed")
+	ErrSignatureInvalid    = errors.New("Token Verifier: JWT Token signature is not valid")
+	ErrTokenExpired        = errors.New("Token Verifier: JWT Token is expired")
+	ErrTokenNotValidYet    = errors.New("Token Verifier: JWT Token is not valid yet")
+	ErrTokenIssuedInFuture = errors.New("Token Verifier: JWT Token is issued in the future")
+	ErrInvalidIssuer       = errors.New("Token Verifier: JWT Token issuer is not accepted")
+	ErrInvalidAudience     = errors.New("Token Verifier: JWT Token audience is not accepted")
+	ErrTokenRevoked        = errors.New("Token Verifier: JWT Token is revoked")
+	ErrTokenInactive       = errors.New("Token Verifier: JWT Token is not active")
+	ErrTokenConsumed       = errors.New("Token Verifier: JWT Token was already used")
+	ErrWrongTokenType      = errors.New("Token Verifier: JWT Token type is not accepted")
+	// ErrMissingClaim matches every MissingClaimError with errors.Is
+	ErrMissingClaim = errors.New("Token Inspector: JWT Token claim is missing")
+	// ErrInvalidClaim matches every InvalidClaimError with errors.Is
+	ErrInvalidClaim = errors.New("Token Inspector: JWT Token claim is not valid")
+)
+
+// ErrKeysUnavailable is returned when the public keys cannot be fetched, it is not a
+// verification error as the token may be valid
+var ErrKeysUnavailable = errors.New("Token Verifier: Public keys are unavailable")
+
+// MissingClaimError is returned when a required claim is not in the token
+type MissingClaimError struct {
+	Name string
+}
+
+// Error returns the error message naming the missing claim
+func (err *MissingClaimError) Error() string {
+	return fmt.Sprintf("Token Inspector: JWT Token claim %s is missing", err.Name)
+}
+
+// Is matches ErrMissingClaim
+func (err *MissingClaimError) Is(target error) bool {
+	return target == ErrMissingClaim
+}
+
+// InvalidClaimError is returned when a claim of the token is not of the expected type
+type InvalidClaimError struct {
+	Name string
+}
+
+// Error returns the error message naming the invalid claim
+func (err *InvalidClaimError) Error() string {
+	return fmt.Sprintf("Token Inspector: JWT Token claim %s is not of valid type", err.Name)
+}
+
+// Is matches ErrInvalidClaim and ErrTokenMalformed
+func (err *InvalidClaimError) Is(target error) bool {
+	return target == ErrInvalidClaim || target == ErrTokenMalformed
+}
+
+// Refresh token rotation errors
+var (
+	ErrRefreshTokenReused         = errors.New("Refresh Token Rotator: Refresh token was already used")
+	ErrRefreshTokenFamilyRevoked  = errors.New("Refresh Token Rotator: Refresh token family is revoked")
+	ErrRefreshTokenFamilyNotFound = errors.New("Refresh Token Rotator: Refresh token family was not found")
+)
@@ -0,0 +1,111 @@
+package jwt
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/log"
+	commonToken "github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+// AuthorizationHeaderKey is the HTTP header carrying the Bearer token
+const AuthorizationHeaderKey = "Authorization"
+
+// ErrorResponse is the JSON body returned when a request is rejected,
+// Reason is the error reason of the token errors, e.g. ReasonTokenExpired
+type ErrorResponse struct {
+	Error  string `json:"error"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// AbortWithStatusError aborts the request with the HTTP status matching the gRPC status
+// or token verification error, see HTTPStatusFromError
+func AbortWithStatusError(c *gin.Context, err error) {
+	statusError := status.Convert(toLoggedStatusError(c.Request.Context(), err))
+	c.AbortWithStatusJSON(HTTPStatusFromCode(statusError.Code()), ErrorResponse{
+		Error:  statusError.Message(),
+		Reason: GetErrorReason(statusError.Err()),
+	})
+}
+
+func getTokenFromRequest(c *gin.Context, cookieName string) (string, error) {
+	if authorization := c.GetHeader(AuthorizationHeaderKey); authorization != "" {
+		return parseBearerToken(authorization)
+	}
+	if cookieName != "" {
+		if cookie, err := c.Cookie(cookieName); err == nil && cookie != "" {
+			return cookie, nil
+		}
+	}
+	return "", status.Error(codes.Unauthenticated, "Authorization token not found in request")
+}
+
+// CreateGinAuthenticationMiddleware is the middleware that verifies the Bearer token of
+// the request and adds its claims to both the Gin and the request contexts.
+// The token is read from the Authorization header or, when cookieName is not empty,
+// from the cookie with that name.
+func CreateGinAuthenticationMiddleware(
+	tokenVerifier TokenVerifierer,
+	tokenInspector TokenInspectorer,
+	cookieName string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, err := getTokenFromRequest(c, cookieName)
+		if err != nil {
+			AbortWithStatusError(c, err)
+			return
+		}
+		claims, err := verifyToken(c.Request.Context(), tokenString, tokenVerifier, tokenInspector)
+		if err != nil {
+			AbortWithStatusError(c, err)
+			return
+		}
+		log.SetAccessLogUserID(c.Request.Context(), claims.UserID)
+		c.Set(string(ClaimsContextKey), claims)
+		c.Set(string(JWTTokenKey), tokenString)
+
+		ctx := context.WithValue(c.Request.Context(), ClaimsContextKey, claims)
+		ctx = context.WithValue(ctx, JWTTokenKey, tokenString)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// GetClaimsFromGinContext gets the claims stored by the authentication middleware
+func GetClaimsFromGinContext(c *gin.Context) (*TokenClaims, error) {
+	if value, exists := c.Get(string(ClaimsContextKey)); exists {
+		if claims, ok := value.(*TokenClaims); ok {
+			return claims, nil
+		}
+	}
+	return GetClaimsFromContext(c.Request.Context())
+}
+
+func requireRule(rule MethodRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := GetClaimsFromGinContext(c)
+		if err != nil {
+			AbortWithStatusError(c, status.Error(codes.Unauthenticated, err.Error()))
+			return
+		}
+		if err := rule.CheckClaims(claims); err != nil {
+			AbortWithStatusError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireTokenType is the middleware that only allows tokens of the given type
+func RequireTokenType(tokenType commonToken.Type) gin.HandlerFunc {
+	return requireRule(MethodRule{TokenType: tokenType})
+}
+
+// RequirePaidFeatures is the middleware that only allows tokens with paid features
+func RequirePaidFeatures() gin.HandlerFunc {
+	return requireRule(MethodRule{PaidFeaturesOnly: true})
+}
@@ -0,0 +1,143 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/log"
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+const testCookieName = "auth_token"
+
+func newTestRouter(components *testComponents) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticated := router.Group("/")
+	authenticated.Use(CreateGinAuthenticationMiddleware(components.verifier, components.inspector, testCookieName))
+	authenticated.Use(RequireTokenType(token.AuthTokenType))
+	authenticated.GET("/user", func(c *gin.Context) {
+		claims, err := GetClaimsFromContext(c.Request.Context())
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, claims.UserID)
+	})
+	authenticated.GET("/paid", RequirePaidFeatures(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestGinAuthenticationMiddleware(t *testing.T) {
+	components := newTestComponents(t)
+	router := newTestRouter(components)
+
+	t.Run("Valid_Header_Token", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/user", nil)
+		request.Header.Set(AuthorizationHeaderKey, BearerPrefix+components.signToken(t, token.AuthTokenType, false))
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "test-user-id", recorder.Body.String())
+	})
+
+	t.Run("Valid_Cookie_Token", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/user", nil)
+		request.AddCookie(&http.Cookie{
+			Name:  testCookieName,
+			Value: components.signToken(t, token.AuthTokenType, false),
+		})
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Missing_Token", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/user", nil)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		var response ErrorResponse
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, "Authorization token not found in request", response.Error)
+	})
+
+	t.Run("Invalid_Token", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/user", nil)
+		request.Header.Set(AuthorizationHeaderKey, BearerPrefix+"invalid.token.value")
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		var response ErrorResponse
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, ReasonTokenMalformed, response.Reason)
+	})
+
+	t.Run("Wrong_Token_Type", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/user", nil)
+		request.Header.Set(AuthorizationHeaderKey, BearerPrefix+components.signToken(t, token.RefreshTokenType, false))
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("Paid_Features_Required", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/paid", nil)
+		request.Header.Set(AuthorizationHeaderKey, BearerPrefix+components.signToken(t, token.AuthTokenType, false))
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
+
+func TestGinAuthenticationMiddlewareAccessLog(t *testing.T) {
+	components := newTestComponents(t)
+	buffer := &bytes.Buffer{}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(log.CreateGinAccessLogMiddleware(log.NewLogFactory("development", log.WithOutput(buffer))))
+	router.Use(CreateGinAuthenticationMiddleware(components.verifier, components.inspector, testCookieName))
+	router.GET("/user", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("Authenticated_Request_Logs_User_ID", func(t *testing.T) {
+		buffer.Reset()
+		request := httptest.NewRequest(http.MethodGet, "/user", nil)
+		request.Header.Set(AuthorizationHeaderKey, BearerPrefix+components.signToken(t, token.AuthTokenType, false))
+
+		router.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Contains(t, buffer.String(), `"status":200`)
+		assert.Contains(t, buffer.String(), `"user_id":"test-user-id"`)
+	})
+
+	t.Run("Rejected_Request_Is_Logged", func(t *testing.T) {
+		buffer.Reset()
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user", nil))
+
+		assert.Contains(t, buffer.String(), `"status":401`)
+		assert.NotContains(t, buffer.String(), "user_id")
+	})
+}
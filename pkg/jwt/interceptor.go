@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/log"
+	commonToken "github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+// Authorization metadata constants
+const (
+	AuthorizationMetadataKey = "authorization"
+	BearerPrefix             = "Bearer "
+)
+
+// MethodRule describes the authentication requirements of a gRPC method
+type MethodRule struct {
+	// Public methods are served without a token
+	Public bool
+	// TokenType is the required token type, empty or AllTokenType accepts any type
+	TokenType commonToken.Type
+	// PaidFeaturesOnly methods require the hasPaidFeatures claim to be true
+	PaidFeaturesOnly bool
+}
+
+// AuthenticationRules maps gRPC full method names to their rules
+type AuthenticationRules struct {
+	DefaultRule MethodRule
+	MethodRules map[string]MethodRule
+}
+
+// GetRule returns the rule for the given full method name
+func (rules *AuthenticationRules) GetRule(fullMethod string) MethodRule {
+	if rules == nil {
+		return MethodRule{}
+	}
+	if rule, ok := rules.MethodRules[fullMethod]; ok {
+		return rule
+	}
+	return rules.DefaultRule
+}
+
+// CheckClaims checks the token claims satisfy the rule
+func (rule MethodRule) CheckClaims(claims *TokenClaims) error {
+	if rule.TokenType != "" &&
+		rule.TokenType != commonToken.AllTokenType &&
+		claims.Type != rule.TokenType {
+		return ToStatusError(fmt.Errorf("%w: %s", ErrWrongTokenType, claims.Type))
+	}
+	if rule.PaidFeaturesOnly && !claims.HasPaidFeatures {
+		return status.Error(codes.PermissionDenied, "Paid features are required")
+	}
+	return nil
+}
+
+// GetBearerTokenFromIncomingContext gets the Bearer token from the incoming metadata
+func GetBearerTokenFromIncomingContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "Metadata not found in context")
+	}
+	values := md.Get(AuthorizationMetadataKey)
+	if len(values) != 1 {
+		return "", status.Error(codes.Unauthenticated, "Authorization token not found in metadata")
+	}
+	return parseBearerToken(values[0])
+}
+
+// GetTokenFromContext gets the raw JWT token from the context
+func GetTokenFromContext(ctx context.Context) (string, error) {
+	if token, ok := ctx.Value(JWTTokenKey).(string); ok {
+		return token, nil
+	}
+	return "", status.Error(codes.Unauthenticated, "Token not found in context")
+}
+
+func parseBearerToken(authorization string) (string, error) {
+	if len(authorization) < len(BearerPrefix) ||
+		!strings.EqualFold(authorization[:len(BearerPrefix)], BearerPrefix) {
+		return "", status.Error(codes.Unauthenticated, "Authorization is not a Bearer token")
+	}
+	token := strings.TrimSpace(authorization[len(BearerPrefix):])
+	if token == "" {
+		return "", status.Error(codes.Unauthenticated, "Bearer token is empty")
+	}
+	return token, nil
+}
+
+func verifyToken(
+	ctx context.Context,
+	tokenString string,
+	tokenVerifier TokenVerifierer,
+	tokenInspector TokenInspectorer,
+) (*TokenClaims, error) {
+	jwtToken, err := tokenVerifier.Verify(tokenString)
+	if err != nil {
+		return nil, toLoggedStatusError(ctx, err)
+	}
+	claims, err := tokenInspector.GetClaimsFromToken(jwtToken)
+	if err != nil {
+		return nil, toLoggedStatusError(ctx, err)
+	}
+	return claims, nil
+}
+
+func authenticateContext(
+	ctx context.Context,
+	fullMethod string,
+	tokenVerifier TokenVerifierer,
+	tokenInspector TokenInspectorer,
+	rules *AuthenticationRules,
+) (context.Context, error) {
+	rule := rules.GetRule(fullMethod)
+	if rule.Public {
+		return ctx, nil
+	}
+	tokenString, err := GetBearerTokenFromIncomingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := verifyToken(ctx, tokenString, tokenVerifier, tokenInspector)
+	if err != nil {
+		return nil, err
+	}
+	log.SetAccessLogUserID(ctx, claims.UserID)
+	if err := rule.CheckClaims(claims); err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, ClaimsContextKey, claims)
+	return context.WithValue(ctx, JWTTokenKey, tokenString), nil
+}
+
+// CreateAuthenticationInterceptor is the interceptor that verifies the Bearer token
+// of the gRPC calls and adds its claims to the context
+func CreateAuthenticationInterceptor(
+	tokenVerifier TokenVerifierer,
+	tokenInspector TokenInspectorer,
+	rules *AuthenticationRules,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		newCtx, err := authenticateContext(ctx, info.FullMethod, tokenVerifier, tokenInspector, rules)
+		if err != nil {
+			return nil, err
+		}
+		return handler(newCtx, req)
+	}
+}
+
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context holding the token claims
+func (stream *authenticatedServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+// CreateAuthenticationStreamInterceptor is the interceptor that verifies the Bearer token
+// of the streaming gRPC calls and adds its claims to the stream context
+func CreateAuthenticationStreamInterceptor(
+	tokenVerifier TokenVerifierer,
+	tokenInspector TokenInspectorer,
+	rules *AuthenticationRules,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		newCtx, err := authenticateContext(stream.Context(), info.FullMethod, tokenVerifier, tokenInspector, rules)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedServerStream{
+			ServerStream: stream,
+			ctx:          newCtx,
+		})
+	}
+}
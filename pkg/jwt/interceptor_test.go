@@ -0,0 +1,285 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/log"
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+const (
+	testMethod       = "/pb_test.TestService/Test"
+	testPublicMethod = "/pb_test.TestService/Public"
+	testPaidMethod   = "/pb_test.TestService/Paid"
+)
+
+type testComponents struct {
+	signer    TokenSignerer
+	verifier  TokenVerifierer
+	inspector TokenInspectorer
+}
+
+func newTestComponents(t *testing.T) *testComponents {
+	keyManager, err := NewKeyManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := keyManager.GetPublicKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenVerifier, err := NewTokenVerifier(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testComponents{
+		signer:    NewTokenSigner(keyManager.GetRSAPrivateKey()),
+		verifier:  tokenVerifier,
+		inspector: &TokenInspector{},
+	}
+}
+
+func (components *testComponents) signToken(t *testing.T, tokenType token.Type, hasPaidFeatures bool) string {
+	tokenString, err := components.signer.SignToken(
+		ClaimPair{EmailClaim, "test@email.com"},
+		ClaimPair{ExpiryClaim, time.Now().Add(time.Hour)},
+		ClaimPair{TypeClaim, tokenType},
+		ClaimPair{UserIDClaim, "test-user-id"},
+		ClaimPair{HasPaidFeaturesClaim, hasPaidFeatures},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *tokenString
+}
+
+func contextWithAuthorization(authorization string) context.Context {
+	md := metadata.New(map[string]string{
+		AuthorizationMetadataKey: authorization,
+	})
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestAuthenticationInterceptor(t *testing.T) {
+	components := newTestComponents(t)
+	rules := &AuthenticationRules{
+		DefaultRule: MethodRule{TokenType: token.AuthTokenType},
+		MethodRules: map[string]MethodRule{
+			testPublicMethod: {Public: true},
+			testPaidMethod:   {TokenType: token.AuthTokenType, PaidFeaturesOnly: true},
+		},
+	}
+	interceptor := CreateAuthenticationInterceptor(components.verifier, components.inspector, rules)
+
+	var handlerCtx context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCtx = ctx
+		return "response", nil
+	}
+
+	t.Run("Valid_Token", func(t *testing.T) {
+		tokenString := components.signToken(t, token.AuthTokenType, false)
+		ctx := contextWithAuthorization(BearerPrefix + tokenString)
+
+		response, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "response", response)
+		claims, err := GetClaimsFromContext(handlerCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-user-id", claims.UserID)
+		assert.Equal(t, token.AuthTokenType, claims.Type)
+		rawToken, err := GetTokenFromContext(handlerCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, tokenString, rawToken)
+	})
+
+	t.Run("Public_Method_Without_Token", func(t *testing.T) {
+		_, err := interceptor(context.Background(), "request", &grpc.UnaryServerInfo{FullMethod: testPublicMethod}, handler)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Missing_Metadata", func(t *testing.T) {
+		_, err := interceptor(context.Background(), "request", &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Not_Bearer_Token", func(t *testing.T) {
+		tokenString := components.signToken(t, token.AuthTokenType, false)
+		ctx := contextWithAuthorization("Basic " + tokenString)
+
+		_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Invalid_Token", func(t *testing.T) {
+		ctx := contextWithAuthorization(BearerPrefix + "invalid.token.value")
+
+		_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Expired_Token", func(t *testing.T) {
+		tokenString, err := components.signer.SignToken(
+			ClaimPair{ExpiryClaim, time.Now().Add(-time.Minute)},
+			ClaimPair{TypeClaim, token.AuthTokenType},
+		)
+		assert.NoError(t, err)
+		ctx := contextWithAuthorization(BearerPrefix + *tokenString)
+
+		_, err = interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Equal(t, ReasonTokenExpired, GetErrorReason(err))
+		assert.True(t, errors.Is(ErrorFromStatus(err), ErrTokenExpired))
+	})
+
+	t.Run("Wrong_Token_Type", func(t *testing.T) {
+		tokenString := components.signToken(t, token.RefreshTokenType, false)
+		ctx := contextWithAuthorization(BearerPrefix + tokenString)
+
+		_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Paid_Features_Required", func(t *testing.T) {
+		tokenString := components.signToken(t, token.AuthTokenType, false)
+		ctx := contextWithAuthorization(BearerPrefix + tokenString)
+
+		_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testPaidMethod}, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Paid_Features_Granted", func(t *testing.T) {
+		tokenString := components.signToken(t, token.AuthTokenType, true)
+		ctx := contextWithAuthorization(BearerPrefix + tokenString)
+
+		_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testPaidMethod}, handler)
+
+		assert.NoError(t, err)
+	})
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestAuthenticationStreamInterceptor(t *testing.T) {
+	components := newTestComponents(t)
+	interceptor := CreateAuthenticationStreamInterceptor(components.verifier, components.inspector, nil)
+
+	t.Run("Valid_Token", func(t *testing.T) {
+		tokenString := components.signToken(t, token.AuthTokenType, false)
+		stream := &testServerStream{ctx: contextWithAuthorization(BearerPrefix + tokenString)}
+
+		err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: testMethod}, func(srv interface{}, stream grpc.ServerStream) error {
+			claims, err := GetClaimsFromContext(stream.Context())
+			assert.NoError(t, err)
+			assert.Equal(t, "test@email.com", claims.Email)
+			return nil
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Missing_Token", func(t *testing.T) {
+		stream := &testServerStream{ctx: context.Background()}
+
+		err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: testMethod}, func(srv interface{}, stream grpc.ServerStream) error {
+			t.Fatal("Handler should not be called")
+			return nil
+		})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestAuthenticationInterceptorAccessLog(t *testing.T) {
+	components := newTestComponents(t)
+	rules := &AuthenticationRules{
+		DefaultRule: MethodRule{TokenType: token.AuthTokenType},
+	}
+	buffer := &bytes.Buffer{}
+	accessLogInterceptor := log.CreateAccessLogInterceptor(log.NewLogFactory("development", log.WithOutput(buffer)))
+	authenticationInterceptor := CreateAuthenticationInterceptor(components.verifier, components.inspector, rules)
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	call := func(ctx context.Context) error {
+		_, err := accessLogInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return authenticationInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			})
+		})
+		return err
+	}
+
+	t.Run("Authenticated_Call_Logs_User_ID", func(t *testing.T) {
+		buffer.Reset()
+
+		err := call(contextWithAuthorization(BearerPrefix + components.signToken(t, token.AuthTokenType, false)))
+
+		assert.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"user_id":"test-user-id"`)
+		assert.Contains(t, buffer.String(), `"code":"OK"`)
+	})
+
+	t.Run("Rejected_Call_Is_Logged", func(t *testing.T) {
+		buffer.Reset()
+
+		err := call(contextWithAuthorization(BearerPrefix + "invalid-token"))
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Contains(t, buffer.String(), log.AccessLogMessage)
+		assert.Contains(t, buffer.String(), `"code":"Unauthenticated"`)
+		assert.NotContains(t, buffer.String(), "user_id")
+	})
+
+	t.Run("Wrong_Token_Type_Logs_User_ID", func(t *testing.T) {
+		buffer.Reset()
+
+		err := call(contextWithAuthorization(BearerPrefix + components.signToken(t, token.RefreshTokenType, false)))
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Contains(t, buffer.String(), `"code":"PermissionDenied"`)
+		assert.Contains(t, buffer.String(), `"user_id":"test-user-id"`)
+	})
+}
+
+func TestAuthenticationInterceptorUnavailableVerification(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logFactory := log.NewLogFactory("development", log.WithOutput(buffer))
+	verifier := NewTokenVerifierWithKeySource(NewCachingKeySource(&failingKeyFetcher{}, time.Hour))
+	interceptor := CreateAuthenticationInterceptor(verifier, &TokenInspector{}, &AuthenticationRules{})
+	components := newTestComponents(t)
+	ctx := contextWithAuthorization(BearerPrefix + components.signToken(t, token.AuthTokenType, false))
+	ctx = context.WithValue(ctx, log.LoggerKey, logFactory.NewLogger())
+
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, VerificationUnavailableMessage, status.Convert(err).Message())
+	assert.NotContains(t, err.Error(), "connection refused")
+	assert.Contains(t, buffer.String(), "connection refused")
+}
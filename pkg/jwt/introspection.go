@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+// Token introspection constants, see RFC 7662
+const (
+	IntrospectionPath           = "/oauth/introspect"
+	IntrospectionTokenParam     = "token"
+	IntrospectionActiveMember   = "active"
+	IntrospectionScopeMember    = "scope"
+	IntrospectionInvalidRequest = "invalid_request"
+	// IntrospectionTemporarilyUnavailable is the error when the token cannot be verified,
+	// the cause is only logged
+	IntrospectionTemporarilyUnavailable = "temporarily_unavailable"
+)
+
+// IntrospectionResponse is a token introspection response, the claims of active tokens
+// are top level members of the JSON object next to active
+type IntrospectionResponse struct {
+	Active bool
+	Claims map[string]interface{}
+}
+
+// MarshalJSON encodes the response as an RFC 7662 JSON object, the scopes are also
+// encoded as the space separated scope member
+func (response IntrospectionResponse) MarshalJSON() ([]byte, error) {
+	members := map[string]interface{}{}
+	if response.Active {
+		for key, value := range response.Claims {
+			members[key] = value
+		}
+		if scopes, ok := response.Claims[ScopesClaim].([]interface{}); ok {
+			scopeNames := make([]string, 0, len(scopes))
+			for _, scope := range scopes {
+				if scopeName, ok := scope.(string); ok {
+					scopeNames = append(scopeNames, scopeName)
+				}
+			}
+			members[IntrospectionScopeMember] = strings.Join(scopeNames, " ")
+		}
+	}
+	members[IntrospectionActiveMember] = response.Active
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON decodes an RFC 7662 JSON object
+func (response *IntrospectionResponse) UnmarshalJSON(data []byte) error {
+	var members map[string]interface{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	response.Active, _ = members[IntrospectionActiveMember].(bool)
+	delete(members, IntrospectionActiveMember)
+	delete(members, IntrospectionScopeMember)
+	response.Claims = members
+	return nil
+}
+
+// TokenIntrospectorer introspects tokens
+type TokenIntrospectorer interface {
+	Introspect(ctx context.Context, token string) (*IntrospectionResponse, error)
+}
+
+// TokenIntrospector introspects tokens with a token verifier
+type TokenIntrospector struct {
+	tokenVerifier TokenVerifierer
+	revoker       Revokerer
+}
+
+var _ TokenIntrospectorer = &TokenIntrospector{}
+
+// TokenIntrospectorOption configures a TokenIntrospector
+type TokenIntrospectorOption func(*TokenIntrospector)
+
+// WithIntrospectionRevoker reports the tokens revoked through the revoker as inactive,
+// for token verifiers created without WithRevoker
+func WithIntrospectionRevoker(revoker Revokerer) TokenIntrospectorOption {
+	return func(introspector *TokenIntrospector) {
+		introspector.revoker = revoker
+	}
+}
+
+// NewTokenIntrospector creates a new token introspector, the tokens the verifier rejects are inactive
+func NewTokenIntrospector(tokenVerifier TokenVerifierer, options ...TokenIntrospectorOption) TokenIntrospectorer {
+	introspector := &TokenIntrospector{
+		tokenVerifier: tokenVerifier,
+	}
+	for _, option := range options {
+		option(introspector)
+	}
+	return introspector
+}
+
+// Introspect verifies the token and returns its claims when it is active, it can back both the
+// HTTP introspection endpoint and gRPC methods. Invalid tokens are inactive and only failures
+// to verify the token, e.g. of the revocation store, are returned as errors.
+func (introspector *TokenIntrospector) Introspect(ctx context.Context, token string) (*IntrospectionResponse, error) {
+	jwtToken, err := introspector.tokenVerifier.Verify(token)
+	if err != nil {
+		if isVerificationError(err) {
+			return &IntrospectionResponse{Active: false}, nil
+		}
+		return nil, err
+	}
+	if introspector.revoker != nil {
+		revoked, err := isTokenRevoked(ctx, introspector.revoker, jwtToken)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return &IntrospectionResponse{Active: false}, nil
+		}
+	}
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return &IntrospectionResponse{Active: false}, nil
+	}
+	return &IntrospectionResponse{
+		Active: true,
+		Claims: claims,
+	}, nil
+}
+
+// CreateGinIntrospectionHandler is the RFC 7662 token introspection handler, reading the token
+// form parameter of POST requests. RFC 7662 requires the callers to be authenticated, e.g. with
+// CreateGinAuthenticationMiddleware, so the endpoint cannot be used to scan for valid tokens.
+func CreateGinIntrospectionHandler(introspector TokenIntrospectorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.PostForm(IntrospectionTokenParam)
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: IntrospectionInvalidRequest})
+			return
+		}
+		response, err := introspector.Introspect(c.Request.Context(), token)
+		if err != nil {
+			logUnavailableError(c.Request.Context(), err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrorResponse{Error: IntrospectionTemporarilyUnavailable})
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
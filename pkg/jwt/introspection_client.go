@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// DefaultIntrospectionTimeout is the timeout of the introspection requests
+const DefaultIntrospectionTimeout = 10 * time.Second
+
+// IntrospectionClient verifies tokens through a token introspection endpoint, so consumers
+// without access to the signing keys can verify them, including opaque tokens
+type IntrospectionClient struct {
+	url         string
+	httpClient  *http.Client
+	tokenSource TokenSource
+	timeout     time.Duration
+}
+
+var (
+	_ TokenVerifierer     = &IntrospectionClient{}
+	_ TokenIntrospectorer = &IntrospectionClient{}
+)
+
+// IntrospectionClientOption configures an IntrospectionClient
+type IntrospectionClientOption func(*IntrospectionClient)
+
+// WithIntrospectionTokenSource authenticates the introspection requests with the Bearer token of the source
+func WithIntrospectionTokenSource(tokenSource TokenSource) IntrospectionClientOption {
+	return func(client *IntrospectionClient) {
+		client.tokenSource = tokenSource
+	}
+}
+
+// WithIntrospectionTimeout sets the timeout of the introspection requests
+func WithIntrospectionTimeout(timeout time.Duration) IntrospectionClientOption {
+	return func(client *IntrospectionClient) {
+		client.timeout = timeout
+	}
+}
+
+// NewIntrospectionClient creates a token verifier calling the introspection endpoint at the URL
+func NewIntrospectionClient(
+	url string,
+	httpClient *http.Client,
+	options ...IntrospectionClientOption,
+) *IntrospectionClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client := &IntrospectionClient{
+		url:        url,
+		httpClient: httpClient,
+		timeout:    DefaultIntrospectionTimeout,
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+// Introspect calls the introspection endpoint
+func (client *IntrospectionClient) Introspect(ctx context.Context, token string) (*IntrospectionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+	form := url.Values{IntrospectionTokenParam: {token}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, client.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if client.tokenSource != nil {
+		bearerToken, err := client.tokenSource.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get introspection token: %v", err)
+		}
+		request.Header.Set(AuthorizationHeaderKey, BearerPrefix+bearerToken)
+	}
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected introspection response status: %s", response.Status)
+	}
+	var introspectionResponse IntrospectionResponse
+	if err := json.NewDecoder(response.Body).Decode(&introspectionResponse); err != nil {
+		return nil, fmt.Errorf("Failed to decode introspection response: %v", err)
+	}
+	return &introspectionResponse, nil
+}
+
+// Verify verifies a token through the introspection endpoint, inactive tokens return ErrTokenInactive.
+// The returned token holds the introspected claims, its header is only set for JWT tokens.
+func (client *IntrospectionClient) Verify(token string) (*jwt.Token, error) {
+	response, err := client.Introspect(context.Background(), token)
+	if err != nil {
+		return nil, fmt.Errorf("Token Verifier: %v", err)
+	}
+	if !response.Active {
+		return nil, ErrTokenInactive
+	}
+	jwtToken, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		// Opaque tokens have no header
+		jwtToken = &jwt.Token{
+			Raw:    token,
+			Header: map[string]interface{}{},
+		}
+	}
+	jwtToken.Claims = jwt.MapClaims(response.Claims)
+	jwtToken.Valid = true
+	return jwtToken, nil
+}
@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+func newIntrospectionServer(introspector TokenIntrospectorer, authorizations *[]string) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST(IntrospectionPath, func(c *gin.Context) {
+		*authorizations = append(*authorizations, c.GetHeader(AuthorizationHeaderKey))
+	}, CreateGinIntrospectionHandler(introspector))
+	return httptest.NewServer(router)
+}
+
+func TestTokenIntrospection(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	issuer := NewTokenIssuer(NewTokenSignerFromKeyManager(keyManager))
+	revoker := newTestRevoker(t, NewMemoryRevocationStore(), DefaultRefreshTokenLifetime)
+	introspector := NewTokenIntrospector(NewTokenVerifierFromKeyManager(keyManager), WithIntrospectionRevoker(revoker))
+	var authorizations []string
+	server := newIntrospectionServer(introspector, &authorizations)
+	defer server.Close()
+	client := NewIntrospectionClient(
+		server.URL+IntrospectionPath,
+		server.Client(),
+		WithIntrospectionTokenSource(NewStaticTokenSource("introspection-token")),
+	)
+
+	t.Run("Active_Token", func(t *testing.T) {
+		authToken, err := issuer.IssueAuthToken("test@email.com", "test-user-id", true, ClaimPair{ScopesClaim, []string{"images:read", "images:write"}})
+		assert.NoError(t, err)
+
+		jwtToken, err := client.Verify(authToken.Token)
+		assert.NoError(t, err)
+		claims, err := (&TokenInspector{}).GetClaimsFromToken(jwtToken)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-user-id", claims.UserID)
+		assert.Equal(t, token.AuthTokenType, claims.Type)
+		assert.True(t, claims.HasPaidFeatures)
+		assert.Equal(t, []string{"images:read", "images:write"}, claims.Scopes)
+		assert.Equal(t, keyManager.GetKeyID(), jwtToken.Header[KeyIDHeader])
+		assert.Equal(t, BearerPrefix+"introspection-token", authorizations[len(authorizations)-1])
+	})
+
+	t.Run("Response_Members", func(t *testing.T) {
+		authToken, err := issuer.IssueAuthToken("test@email.com", "test-user-id", false, ClaimPair{ScopesClaim, []string{"images:read", "images:write"}})
+		assert.NoError(t, err)
+		form := url.Values{IntrospectionTokenParam: {authToken.Token}}
+
+		response, err := server.Client().Post(server.URL+IntrospectionPath, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		var members map[string]interface{}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&members))
+		assert.Equal(t, true, members[IntrospectionActiveMember])
+		assert.Equal(t, "images:read images:write", members[IntrospectionScopeMember])
+		assert.Equal(t, "test-user-id", members[UserIDClaim])
+		assert.Equal(t, "no-store", response.Header.Get("Cache-Control"))
+	})
+
+	t.Run("Revoked_Token", func(t *testing.T) {
+		authToken, err := issuer.IssueAuthToken("test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+		assert.NoError(t, revoker.RevokeToken(context.Background(), authToken.Claims.JWTID, authToken.Claims.Expiry))
+
+		_, err = client.Verify(authToken.Token)
+		assert.True(t, errors.Is(err, ErrTokenInactive))
+	})
+
+	t.Run("Expired_Token", func(t *testing.T) {
+		tokenString, err := NewTokenSignerFromKeyManager(keyManager).SignToken(ClaimPair{ExpiryClaim, time.Now().Add(-time.Minute)})
+		assert.NoError(t, err)
+
+		response, err := introspector.Introspect(context.Background(), *tokenString)
+		assert.NoError(t, err)
+		assert.False(t, response.Active)
+		_, err = client.Verify(*tokenString)
+		assert.True(t, errors.Is(err, ErrTokenInactive))
+	})
+
+	t.Run("Opaque_Token", func(t *testing.T) {
+		_, err := client.Verify("opaque-token")
+		assert.True(t, errors.Is(err, ErrTokenInactive))
+	})
+
+	t.Run("Missing_Token", func(t *testing.T) {
+		response, err := server.Client().Post(server.URL+IntrospectionPath, "application/x-www-form-urlencoded", nil)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestTokenIntrospectionUnavailable(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	verifier := NewTokenVerifierWithKeySource(NewCachingKeySource(&failingKeyFetcher{}, time.Hour))
+	var authorizations []string
+	server := newIntrospectionServer(NewTokenIntrospector(verifier), &authorizations)
+	defer server.Close()
+
+	form := url.Values{IntrospectionTokenParam: {signTestToken(t, NewTokenSignerFromKeyManager(keyManager))}}
+	response, err := server.Client().PostForm(server.URL+IntrospectionPath, form)
+	assert.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	var errorResponse ErrorResponse
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&errorResponse))
+	assert.Equal(t, IntrospectionTemporarilyUnavailable, errorResponse.Error)
+}
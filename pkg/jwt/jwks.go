@@ -0,0 +1,170 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSPath is the well-known path where the JSON Web Key Set is published
+const JWKSPath = "/.well-known/jwks.json"
+
+// JSONWebKey is a public key in the JSON Web Key (RFC 7517) format
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is a set of JSON Web Keys
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// NewJSONWebKey creates the JSON Web Key of a public key, the algorithm is
+// only set when the key type determines it
+func NewJSONWebKey(keyID string, publicKey crypto.PublicKey) (JSONWebKey, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JSONWebKey{
+			KeyType: "RSA",
+			Use:     "sig",
+			KeyID:   keyID,
+			N:       encodeBigInt(key.N),
+			E:       encodeBigInt(big.NewInt(int64(key.E))),
+		}, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return JSONWebKey{}, fmt.Errorf("Unsupported elliptic curve: %s", key.Curve.Params().Name)
+		}
+		return JSONWebKey{
+			KeyType:   "EC",
+			Use:       "sig",
+			Algorithm: string(ES256),
+			KeyID:     keyID,
+			Curve:     key.Curve.Params().Name,
+			X:         encodeCoordinate(key.X, key.Curve),
+			Y:         encodeCoordinate(key.Y, key.Curve),
+		}, nil
+	case ed25519.PublicKey:
+		return JSONWebKey{
+			KeyType:   "OKP",
+			Use:       "sig",
+			Algorithm: string(EdDSA),
+			KeyID:     keyID,
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(key),
+		}, nil
+	default:
+		return JSONWebKey{}, fmt.Errorf("Unsupported public key type: %T", publicKey)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// PublicKey decodes the public key of the JSON Web Key
+func (key JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch key.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JSON Web Key modulus: %v", err)
+		}
+		e, err := decodeBigInt(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JSON Web Key exponent: %v", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if key.Curve != elliptic.P256().Params().Name {
+			return nil, fmt.Errorf("Unsupported JSON Web Key curve: %s", key.Curve)
+		}
+		x, err := decodeBigInt(key.X)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JSON Web Key x coordinate: %v", err)
+		}
+		y, err := decodeBigInt(key.Y)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JSON Web Key y coordinate: %v", err)
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, fmt.Errorf("JSON Web Key point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	case "OKP":
+		if key.Curve != "Ed25519" {
+			return nil, fmt.Errorf("Unsupported JSON Web Key curve: %s", key.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Invalid JSON Web Key Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("Unsupported JSON Web Key type: %s", key.KeyType)
+	}
+}
+
+// NewJSONWebKeySet creates a JSON Web Key Set from public keys indexed by key ID
+func NewJSONWebKeySet(publicKeys map[string]crypto.PublicKey) (*JSONWebKeySet, error) {
+	keySet := &JSONWebKeySet{
+		Keys: make([]JSONWebKey, 0, len(publicKeys)),
+	}
+	for keyID, publicKey := range publicKeys {
+		key, err := NewJSONWebKey(keyID, publicKey)
+		if err != nil {
+			return nil, err
+		}
+		keySet.Keys = append(keySet.Keys, key)
+	}
+	return keySet, nil
+}
+
+// PublicKeys decodes the public keys of the set indexed by key ID
+func (keySet *JSONWebKeySet) PublicKeys() (map[string]crypto.PublicKey, error) {
+	publicKeys := map[string]crypto.PublicKey{}
+	for _, key := range keySet.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		publicKey, err := key.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode key %s: %v", key.KeyID, err)
+		}
+		publicKeys[key.KeyID] = publicKey
+	}
+	return publicKeys, nil
+}
+
+// CreateGinJWKSHandler is the handler that publishes the key manager public keys as a JSON Web Key Set
+func CreateGinJWKSHandler(keyManager KeyManagerer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keySet, err := keyManager.GetJSONWebKeySet()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(DefaultKeyCacheTTL.Seconds())))
+		c.JSON(http.StatusOK, keySet)
+	}
+}
@@ -11,7 +11,7 @@ import (
 )
 
 func TestTokenInspector(t *testing.T) {
-	keyManager, err := NewKeyManager("./keys")
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
 	if err != nil {
 		t.Fatal(err)
 	}
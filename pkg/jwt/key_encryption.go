@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Private key envelope constants, the envelope PEM block holds the DER bytes of
+// the private key PEM block encrypted with AES-256-GCM under a scrypt derived key
+const (
+	EncryptedPrivateKeyType = "ENCRYPTED PRIVATE KEY ENVELOPE"
+	ContentTypePEMHeader    = "Content-Type"
+	KDFPEMHeader            = "KDF"
+	KDFParamsPEMHeader      = "KDF-Params"
+	SaltPEMHeader           = "Salt"
+	CipherPEMHeader         = "Cipher"
+	NoncePEMHeader          = "Nonce"
+	ScryptKDF               = "scrypt"
+	AES256GCMCipher         = "AES-256-GCM"
+	PrivateKeyFileMode      = 0600
+)
+
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	scryptKeySize = 32
+	saltSize      = 16
+)
+
+// ErrMissingPassphrase is returned when loading an encrypted private key without a passphrase provider
+var ErrMissingPassphrase = errors.New("Key Manager: Private key is encrypted and no passphrase provider is configured")
+
+// PassphraseProvider provides the passphrase encrypting the private key at rest
+type PassphraseProvider interface {
+	GetPassphrase() ([]byte, error)
+}
+
+// PassphraseProviderFunc is a function providing the passphrase
+type PassphraseProviderFunc func() ([]byte, error)
+
+// GetPassphrase calls the function
+func (providerFunc PassphraseProviderFunc) GetPassphrase() ([]byte, error) {
+	return providerFunc()
+}
+
+// EnvPassphraseProvider reads the passphrase from an environment variable
+type EnvPassphraseProvider struct {
+	VariableName string
+}
+
+// NewEnvPassphraseProvider creates a new environment variable passphrase provider
+func NewEnvPassphraseProvider(variableName string) *EnvPassphraseProvider {
+	return &EnvPassphraseProvider{VariableName: variableName}
+}
+
+// GetPassphrase gets the value of the environment variable
+func (provider *EnvPassphraseProvider) GetPassphrase() ([]byte, error) {
+	passphrase, exists := os.LookupEnv(provider.VariableName)
+	if !exists || passphrase == "" {
+		return nil, fmt.Errorf("Passphrase environment variable %s is not set", provider.VariableName)
+	}
+	return []byte(passphrase), nil
+}
+
+// FilePassphraseProvider reads the passphrase from a file, e.g. a mounted secret
+type FilePassphraseProvider struct {
+	FileName string
+}
+
+// NewFilePassphraseProvider creates a new file passphrase provider
+func NewFilePassphraseProvider(fileName string) *FilePassphraseProvider {
+	return &FilePassphraseProvider{FileName: fileName}
+}
+
+// GetPassphrase gets the content of the file without the trailing new lines
+func (provider *FilePassphraseProvider) GetPassphrase() ([]byte, error) {
+	content, err := os.ReadFile(provider.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read passphrase file: %v", err)
+	}
+	passphrase := bytes.TrimRight(content, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("Passphrase file %s is empty", provider.FileName)
+	}
+	return passphrase, nil
+}
+
+func newKeyEncryptionCipher(passphrase, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, scryptKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptPrivateKeyBlock(block *pem.Block, passphraseProvider PassphraseProvider) (*pem.Block, error) {
+	passphrase, err := passphraseProvider.GetPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newKeyEncryptionCipher(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &pem.Block{
+		Type: EncryptedPrivateKeyType,
+		Headers: map[string]string{
+			ContentTypePEMHeader: block.Type,
+			KDFPEMHeader:         ScryptKDF,
+			KDFParamsPEMHeader:   fmt.Sprintf("%d,%d,%d", scryptN, scryptR, scryptP),
+			SaltPEMHeader:        hex.EncodeToString(salt),
+			CipherPEMHeader:      AES256GCMCipher,
+			NoncePEMHeader:       hex.EncodeToString(nonce),
+		},
+		// The content type is authenticated so it cannot be swapped
+		Bytes: aead.Seal(nil, nonce, block.Bytes, []byte(block.Type)),
+	}, nil
+}
+
+func parseScryptParams(kdfParams string) (int, int, int, error) {
+	var n, r, p int
+	if _, err := fmt.Sscanf(kdfParams, "%d,%d,%d", &n, &r, &p); err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid scrypt parameters %q: %v", kdfParams, err)
+	}
+	return n, r, p, nil
+}
+
+func decryptPrivateKeyBlock(block *pem.Block, passphraseProvider PassphraseProvider) (*pem.Block, error) {
+	if passphraseProvider == nil {
+		return nil, ErrMissingPassphrase
+	}
+	if block.Headers[KDFPEMHeader] != ScryptKDF || block.Headers[CipherPEMHeader] != AES256GCMCipher {
+		return nil, fmt.Errorf(
+			"Unsupported private key encryption: %s %s",
+			block.Headers[KDFPEMHeader],
+			block.Headers[CipherPEMHeader],
+		)
+	}
+	n, r, p, err := parseScryptParams(block.Headers[KDFParamsPEMHeader])
+	if err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(block.Headers[SaltPEMHeader])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid private key salt: %v", err)
+	}
+	nonce, err := hex.DecodeString(block.Headers[NoncePEMHeader])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid private key nonce: %v", err)
+	}
+	passphrase, err := passphraseProvider.GetPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newKeyEncryptionCipher(passphrase, salt, n, r, p)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("Invalid private key nonce size")
+	}
+	contentType := block.Headers[ContentTypePEMHeader]
+	keyBytes, err := aead.Open(nil, nonce, block.Bytes, []byte(contentType))
+	if err != nil {
+		return nil, errors.New("Failed to decrypt private key, the passphrase may be wrong")
+	}
+	return &pem.Block{
+		Type:  contentType,
+		Bytes: keyBytes,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func staticPassphrase(passphrase string) PassphraseProvider {
+	return PassphraseProviderFunc(func() ([]byte, error) {
+		return []byte(passphrase), nil
+	})
+}
+
+func readPrivateKeyFile(t *testing.T, keyLocation string) (string, os.FileMode) {
+	fileName := fmt.Sprintf("%s/%s", keyLocation, PrivateKeyFileName)
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content), info.Mode().Perm()
+}
+
+func TestKeyManagerEncryptedPrivateKey(t *testing.T) {
+	t.Run("Private_Key_Is_Encrypted_At_Rest", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(keyLocation, WithPassphraseProvider(staticPassphrase("test-passphrase")))
+		assert.NoError(t, err)
+
+		content, mode := readPrivateKeyFile(t, keyLocation)
+		assert.Equal(t, os.FileMode(PrivateKeyFileMode), mode)
+		assert.True(t, strings.HasPrefix(content, "-----BEGIN "+EncryptedPrivateKeyType))
+		assert.NotContains(t, content, "BEGIN "+PrivateKeyType)
+
+		reloadedKeyManager, err := NewKeyManager(keyLocation, WithPassphraseProvider(staticPassphrase("test-passphrase")))
+		assert.NoError(t, err)
+		assert.Equal(t, keyManager.GetKeyID(), reloadedKeyManager.GetKeyID())
+		_, err = newVerifierFromKeyManager(t, keyManager).Verify(signTestToken(t, NewTokenSignerFromKeyManager(reloadedKeyManager)))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Wrong_Passphrase", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		_, err := NewKeyManager(keyLocation, WithPassphraseProvider(staticPassphrase("test-passphrase")))
+		assert.NoError(t, err)
+
+		_, err = NewKeyManager(keyLocation, WithPassphraseProvider(staticPassphrase("wrong-passphrase")))
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing_Passphrase", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		_, err := NewKeyManager(keyLocation, WithPassphraseProvider(staticPassphrase("test-passphrase")))
+		assert.NoError(t, err)
+
+		_, err = NewKeyManager(keyLocation)
+		assert.True(t, errors.Is(err, ErrMissingPassphrase))
+	})
+
+	t.Run("Plaintext_Key_Is_Encrypted_On_Load", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		fileName := fmt.Sprintf("%s/%s", keyLocation, PrivateKeyFileName)
+		assert.NoError(t, os.Chmod(fileName, 0644))
+
+		encryptedKeyManager, err := NewKeyManager(keyLocation, WithPassphraseProvider(staticPassphrase("test-passphrase")))
+		assert.NoError(t, err)
+		assert.Equal(t, keyManager.GetKeyID(), encryptedKeyManager.GetKeyID())
+		content, mode := readPrivateKeyFile(t, keyLocation)
+		assert.Equal(t, os.FileMode(PrivateKeyFileMode), mode)
+		assert.Contains(t, content, EncryptedPrivateKeyType)
+	})
+
+	t.Run("Rotated_Keys_Are_Encrypted", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(
+			keyLocation,
+			WithAlgorithm(ES256),
+			WithPassphraseProvider(staticPassphrase("test-passphrase")),
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, keyManager.GenerateNewKeyPair())
+
+		content, _ := readPrivateKeyFile(t, keyLocation)
+		assert.Contains(t, content, EncryptedPrivateKeyType)
+		assert.Contains(t, content, ContentTypePEMHeader+": "+PKCS8PrivateKeyType)
+	})
+}
+
+func TestPassphraseProviders(t *testing.T) {
+	t.Run("Environment_Variable", func(t *testing.T) {
+		t.Setenv("TEST_KEY_PASSPHRASE", "env-passphrase")
+
+		passphrase, err := NewEnvPassphraseProvider("TEST_KEY_PASSPHRASE").GetPassphrase()
+		assert.NoError(t, err)
+		assert.Equal(t, "env-passphrase", string(passphrase))
+
+		_, err = NewEnvPassphraseProvider("TEST_MISSING_KEY_PASSPHRASE").GetPassphrase()
+		assert.Error(t, err)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		fileName := fmt.Sprintf("%s/passphrase", t.TempDir())
+		assert.NoError(t, os.WriteFile(fileName, []byte("file-passphrase\n"), 0600))
+
+		passphrase, err := NewFilePassphraseProvider(fileName).GetPassphrase()
+		assert.NoError(t, err)
+		assert.Equal(t, "file-passphrase", string(passphrase))
+
+		_, err = NewFilePassphraseProvider(fileName + ".missing").GetPassphrase()
+		assert.Error(t, err)
+	})
+}
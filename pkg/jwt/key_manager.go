@@ -2,210 +2,696 @@ package jwt
 
 import (
 	"context"
-	"crypto/rand"
+	"crypto"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/quadev-ltd/qd-common/pkg/fs"
+	"github.com/quadev-ltd/qd-common/pkg/config"
+	"github.com/quadev-ltd/qd-common/pkg/log"
 )
 
 // KeyManagerer handles key generation and retrieval
 type KeyManagerer interface {
 	GenerateNewKeyPair() error
+	RotateKeyPair(gracePeriod time.Duration) error
+	GetKeyID() string
+	GetAlgorithm() Algorithm
+	GetPrivateKey() crypto.Signer
+	GetSigningKey() SigningKey
 	GetRSAPrivateKey() *rsa.PrivateKey
 	GetRSAPublicKey() *rsa.PublicKey
+	GetVerificationKeys() map[string]crypto.PublicKey
+	GetJSONWebKeySet() (*JSONWebKeySet, error)
 	GetPublicKey(ctx context.Context) (string, error)
+	Reload() error
+	Watch(ctx context.Context, onError func(error)) error
 }
 
-// KeyManager is responsible for generating and managing RSA keys
+// RetiredKey is a public key no longer used for signing that still verifies
+// tokens until it expires
+type RetiredKey struct {
+	KeyID     string
+	PublicKey crypto.PublicKey
+	ExpiresAt time.Time
+}
+
+// KeyManager is responsible for generating and managing the signing keys,
+// it is safe for concurrent use
 type KeyManager struct {
-	fileLocation string
-	privateKey   *rsa.PrivateKey
-	publicKey    *rsa.PublicKey
-	fs           fs.FileSystem
+	// mutex guards the keys, rotationMutex serializes rotations and reloads
+	mutex              sync.RWMutex
+	rotationMutex      sync.Mutex
+	store              KeyStore
+	gracePeriod        time.Duration
+	algorithm          Algorithm
+	passphraseProvider PassphraseProvider
+	keyID              string
+	privateKey         crypto.Signer
+	publicKey          crypto.PublicKey
+	retiredKeys        []*RetiredKey
 }
 
 var _ KeyManagerer = &KeyManager{}
 
-// NewKeyManager creates a new JWT signer
-func NewKeyManager(fileLocation string) (KeyManagerer, error) {
-	fs := &fs.OSFileSystem{}
-	privateKey, err := loadPrivateKeyFromFile(
-		fmt.Sprintf("%s/%s", fileLocation, PrivateKeyFileName),
-		fs,
-	)
-	if err != nil && fs.IsNotExist(err) {
-		privateKey, publicKey, err := generateKeyFiles(fileLocation, fs)
-		if err != nil {
-			return nil, err
-		}
-		return &KeyManager{
-			privateKey:   privateKey,
-			publicKey:    publicKey,
-			fileLocation: fileLocation,
-			fs:           fs,
-		}, nil
-	} else if err != nil {
+// KeyManagerOption configures a KeyManager
+type KeyManagerOption func(*KeyManager)
+
+// WithGracePeriod sets how long a rotated key keeps verifying tokens
+func WithGracePeriod(gracePeriod time.Duration) KeyManagerOption {
+	return func(keyManager *KeyManager) {
+		keyManager.gracePeriod = gracePeriod
+	}
+}
+
+// WithAlgorithm sets the signing algorithm, an existing key of a different
+// type is rotated on start up
+func WithAlgorithm(algorithm Algorithm) KeyManagerOption {
+	return func(keyManager *KeyManager) {
+		keyManager.algorithm = algorithm
+	}
+}
+
+// WithPassphraseProvider encrypts the private key at rest with the provided passphrase,
+// an existing unencrypted private key is encrypted on start up
+func WithPassphraseProvider(passphraseProvider PassphraseProvider) KeyManagerOption {
+	return func(keyManager *KeyManager) {
+		keyManager.passphraseProvider = passphraseProvider
+	}
+}
+
+// WithKeyStore sets where the keys are stored, instead of the file location directory
+func WithKeyStore(store KeyStore) KeyManagerOption {
+	return func(keyManager *KeyManager) {
+		keyManager.store = store
+	}
+}
+
+// NewKeyManager creates a new key manager storing the keys in the fileLocation
+// directory unless a key store is given
+func NewKeyManager(fileLocation string, options ...KeyManagerOption) (KeyManagerer, error) {
+	keyManager := &KeyManager{
+		gracePeriod: DefaultKeyGracePeriod,
+		algorithm:   DefaultAlgorithm,
+	}
+	for _, option := range options {
+		option(keyManager)
+	}
+	if keyManager.store == nil {
+		keyManager.store = NewDirectoryKeyStore(fileLocation)
+	}
+	if _, err := ParseAlgorithm(string(keyManager.algorithm)); err != nil {
 		return nil, err
 	}
-	publicKey, err := loadPublicKeyFromFile(
-		fmt.Sprintf("%s/%s", fileLocation, PublicKeyFileName),
-		fs,
-	)
+	// Replicas starting together wait for the first one to generate the keys and load them
+	keys, err := keyManager.withKeyStoreLock(keyManager.load)
+	if errors.Is(err, ErrKeyStoreConflict) {
+		// Another replica generated the keys meanwhile, its keys are loaded instead
+		keys, err = keyManager.withKeyStoreLock(keyManager.load)
+	}
+	if err != nil {
+		return nil, err
+	}
+	keyManager.setKeys(keys)
+	return keyManager, nil
+}
+
+// activeKeys are the active key and the retired keys of the key manager
+type activeKeys struct {
+	keyID       string
+	privateKey  crypto.Signer
+	publicKey   crypto.PublicKey
+	retiredKeys []*RetiredKey
+}
+
+func newActiveKeys(privateKey crypto.Signer, retiredKeys []*RetiredKey) (*activeKeys, error) {
+	publicKey := privateKey.Public()
+	keyID, err := KeyID(publicKey)
 	if err != nil {
 		return nil, err
 	}
-	return &KeyManager{
-		privateKey: privateKey,
-		publicKey:  publicKey,
+	return &activeKeys{
+		keyID:       keyID,
+		privateKey:  privateKey,
+		publicKey:   publicKey,
+		retiredKeys: retiredKeys,
 	}, nil
 }
 
-func createKeysFolderIfNotExists(fileLocation string, fs fs.FileSystem) error {
-	if _, err := fs.Stat(fileLocation); fs.IsNotExist(err) {
-		err := fs.Mkdir(fileLocation, 0700)
+// load loads the keys of the store, generating them when there are none
+func (keyManager *KeyManager) load() (*activeKeys, error) {
+	privateKey, encrypted, err := loadPrivateKey(keyManager.store, keyManager.passphraseProvider)
+	if errors.Is(err, ErrKeyNotFound) {
+		privateKey, err := keyManager.generateKeyFiles()
+		if err != nil {
+			return nil, err
+		}
+		return newActiveKeys(privateKey, nil)
+	} else if err != nil {
+		return nil, err
+	}
+	if keyManager.passphraseProvider != nil && !encrypted {
+		err := savePrivateKey(privateKey, keyManager.passphraseProvider, keyManager.store)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("Failed to encrypt private key: %v", err)
 		}
 	}
-	return nil
+	if err := keyManager.checkPublicKey(privateKey.Public()); err != nil {
+		return nil, err
+	}
+	retiredKeys, err := loadRetiredKeys(keyManager.store)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := newActiveKeys(privateKey, retiredKeys)
+	if err != nil {
+		return nil, err
+	}
+	if !keyManager.algorithm.IsCompatibleWith(keys.publicKey) {
+		// The configured algorithm changed to a different key type
+		return keyManager.rotateKeyPair(keys, keyManager.gracePeriod)
+	}
+	keys.retiredKeys, err = keyManager.pruneRetiredKeys(keys.retiredKeys)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
 }
 
-func generateKeyPair() (*rsa.PrivateKey, *rsa.PublicKey, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+// NewKeyManagerFromConfig creates a new key manager signing with the algorithm configured in
+// jwt_algorithm, RS256 when it is empty, the options take precedence over the configuration
+func NewKeyManagerFromConfig(
+	fileLocation string,
+	appConfig *config.Config,
+	options ...KeyManagerOption,
+) (KeyManagerer, error) {
+	algorithm, err := ParseAlgorithm(appConfig.JWTAlgorithm)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return NewKeyManager(fileLocation, append([]KeyManagerOption{WithAlgorithm(algorithm)}, options...)...)
+}
 
-	publicKey := &privateKey.PublicKey
-
-	return privateKey, publicKey, nil
+func lockKeyStore(store KeyStore) (func() error, error) {
+	if locker, ok := store.(KeyStoreLocker); ok {
+		return locker.Lock()
+	}
+	return func() error {
+		return nil
+	}, nil
 }
 
-func savePrivateKeyToFile(privateKey *rsa.PrivateKey, filename string, fs fs.FileSystem) error {
-	file, err := fs.Create(filename)
+// withKeyStoreLock runs the function holding the key store lock, the keys are only returned
+// once the changes made under the lock are saved on releasing it
+func (keyManager *KeyManager) withKeyStoreLock(run func() (*activeKeys, error)) (*activeKeys, error) {
+	unlock, err := lockKeyStore(keyManager.store)
 	if err != nil {
+		return nil, fmt.Errorf("Failed to lock key store: %v", err)
+	}
+	keys, err := run()
+	if unlockErr := unlock(); unlockErr != nil {
+		return nil, errors.Join(err, fmt.Errorf("Failed to save key store: %w", unlockErr))
+	}
+	return keys, err
+}
+
+// checkPublicKey checks the stored public key matches the public key of the private key. The
+// private key is written first, so a mismatch left by a crash while generating keys is repaired
+// by writing the public key of the private key, after logging the mismatch, which can also be a
+// public key replaced by mistake.
+func (keyManager *KeyManager) checkPublicKey(privatePublicKey crypto.PublicKey) error {
+	publicKey, err := loadPublicKey(keyManager.store, PublicKeyFileName)
+	if errors.Is(err, ErrKeyNotFound) {
+		// Stores without a public key, e.g. PEMKeyStore, use the one of the private key
+		return nil
+	} else if err != nil {
 		return err
 	}
-	defer file.Close()
+	if equalPublicKey, ok := publicKey.(interface{ Equal(crypto.PublicKey) bool }); ok &&
+		equalPublicKey.Equal(privatePublicKey) {
+		return nil
+	}
+	storedKeyID, _ := KeyID(publicKey)
+	privateKeyID, _ := KeyID(privatePublicKey)
+	getPackageLogger().Error(
+		errors.New("Public key does not match the private key"),
+		"Repairing the stored public key",
+		log.String("stored_key_id", storedKeyID),
+		log.String("key_id", privateKeyID),
+	)
+	if err := savePublicKey(privatePublicKey, PublicKeyFileName, nil, keyManager.store); err != nil {
+		return fmt.Errorf("Public key does not match the private key and could not be repaired: %v", err)
+	}
+	return nil
+}
 
-	privateKeyBytes := x509.MarshalPKCS1PrivateKey(privateKey)
-	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  PrivateKeyType,
+func newPrivateKeyBlock(privateKey crypto.Signer) (*pem.Block, error) {
+	// RSA keys keep the PKCS1 encoding of the files generated by earlier versions
+	if rsaPrivateKey, ok := privateKey.(*rsa.PrivateKey); ok {
+		return &pem.Block{
+			Type:  PrivateKeyType,
+			Bytes: x509.MarshalPKCS1PrivateKey(rsaPrivateKey),
+		}, nil
+	}
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &pem.Block{
+		Type:  PKCS8PrivateKeyType,
 		Bytes: privateKeyBytes,
-	})
+	}, nil
+}
 
-	_, err = file.Write(privateKeyPEM)
-	return err
+func encodePrivateKeyToPEM(privateKey crypto.Signer, passphraseProvider PassphraseProvider) ([]byte, error) {
+	block, err := newPrivateKeyBlock(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if passphraseProvider != nil {
+		block, err = encryptPrivateKeyBlock(block, passphraseProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pem.EncodeToMemory(block), nil
 }
 
-func savePublicKeyToFile(publicKey *rsa.PublicKey, filename string, fs fs.FileSystem) error {
-	file, err := fs.Create(filename)
+func savePrivateKey(privateKey crypto.Signer, passphraseProvider PassphraseProvider, store KeyStore) error {
+	privateKeyPEM, err := encodePrivateKeyToPEM(privateKey, passphraseProvider)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	return store.Write(PrivateKeyFileName, privateKeyPEM)
+}
 
+func encodePublicKeyToPEM(publicKey crypto.PublicKey, headers map[string]string) ([]byte, error) {
 	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  PublicKeyType,
-		Bytes: publicKeyBytes,
-	})
+	blockType := PKIXPublicKeyType
+	if _, ok := publicKey.(*rsa.PublicKey); ok {
+		blockType = PublicKeyType
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:    blockType,
+		Headers: headers,
+		Bytes:   publicKeyBytes,
+	}), nil
+}
 
-	_, err = file.Write(publicKeyPEM)
-	return err
+func savePublicKey(publicKey crypto.PublicKey, name string, headers map[string]string, store KeyStore) error {
+	publicKeyPEM, err := encodePublicKeyToPEM(publicKey, headers)
+	if err != nil {
+		return err
+	}
+	return store.Write(name, publicKeyPEM)
 }
 
-func loadPrivateKeyFromFile(filename string, fs fs.FileSystem) (*rsa.PrivateKey, error) {
-	privateKeyPEM, err := fs.ReadFile(filename)
+func parsePrivateKeyBlock(block *pem.Block) (crypto.Signer, error) {
+	var privateKey interface{}
+	var err error
+	switch block.Type {
+	case PrivateKeyType:
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case ECPrivateKeyType:
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	case PKCS8PrivateKeyType:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("Unsupported private key PEM type: %s", block.Type)
+	}
 	if err != nil {
 		return nil, err
 	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported private key type: %T", privateKey)
+	}
+	return signer, nil
+}
+
+// loadPrivateKey loads the private key, decrypting it when it is encrypted
+func loadPrivateKey(store KeyStore, passphraseProvider PassphraseProvider) (crypto.Signer, bool, error) {
+	privateKeyPEM, err := store.Read(PrivateKeyFileName)
+	if err != nil {
+		return nil, false, err
+	}
 
 	block, _ := pem.Decode(privateKeyPEM)
-	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if block == nil {
+		return nil, false, fmt.Errorf("No PEM block found in %s", PrivateKeyFileName)
+	}
+	encrypted := block.Type == EncryptedPrivateKeyType
+	if encrypted {
+		block, err = decryptPrivateKeyBlock(block, passphraseProvider)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	privateKey, err := parsePrivateKeyBlock(block)
 	if err != nil {
+		return nil, false, err
+	}
+	return privateKey, encrypted, nil
+}
+
+func parsePublicKeyBlock(block *pem.Block) (crypto.PublicKey, error) {
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := KeyID(publicKey); err != nil {
 		return nil, err
 	}
+	return publicKey, nil
+}
 
-	return privateKey, nil
+func loadPublicKeyBlock(store KeyStore, name string) (*pem.Block, crypto.PublicKey, error) {
+	publicKeyPEM, err := store.Read(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, nil, fmt.Errorf("No PEM block found in %s", name)
+	}
+	publicKey, err := parsePublicKeyBlock(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, publicKey, nil
+}
+
+func loadPublicKey(store KeyStore, name string) (crypto.PublicKey, error) {
+	_, publicKey, err := loadPublicKeyBlock(store, name)
+	return publicKey, err
+}
+
+func retiredPublicKeyFileName(keyID string) string {
+	return fmt.Sprintf(RetiredPublicKeyFileNameFormat, keyID)
+}
+
+func isRetiredPublicKeyFileName(name string) bool {
+	return name != PublicKeyFileName &&
+		strings.HasPrefix(name, RetiredPublicKeyFileNamePrefix) &&
+		strings.HasSuffix(name, RetiredPublicKeyFileNameSuffix)
 }
 
-func loadPublicKeyFromFile(filename string, fs fs.FileSystem) (*rsa.PublicKey, error) {
-	publicKeyPEM, err := fs.ReadFile(filename)
+func loadRetiredKeys(store KeyStore) ([]*RetiredKey, error) {
+	names, err := store.List()
 	if err != nil {
 		return nil, err
 	}
+	retiredKeys := []*RetiredKey{}
+	for _, name := range names {
+		if !isRetiredPublicKeyFileName(name) {
+			continue
+		}
+		block, publicKey, err := loadPublicKeyBlock(store, name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load retired key %s: %v", name, err)
+		}
+		expiresAt, err := time.Parse(time.RFC3339, block.Headers[ExpiresAtPEMHeader])
+		if err != nil {
+			return nil, fmt.Errorf("Retired key %s has an invalid expiry: %v", name, err)
+		}
+		keyID, err := KeyID(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		retiredKeys = append(retiredKeys, &RetiredKey{
+			KeyID:     keyID,
+			PublicKey: publicKey,
+			ExpiresAt: expiresAt,
+		})
+	}
+	sort.Slice(retiredKeys, func(i, j int) bool {
+		return retiredKeys[i].ExpiresAt.After(retiredKeys[j].ExpiresAt)
+	})
+	return retiredKeys, nil
+}
 
-	block, _ := pem.Decode(publicKeyPEM)
-	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+func (keyManager *KeyManager) generateKeyFiles() (crypto.Signer, error) {
+	privateKey, err := keyManager.algorithm.GenerateKey()
 	if err != nil {
 		return nil, err
 	}
+	err = savePrivateKey(privateKey, keyManager.passphraseProvider, keyManager.store)
+	if err != nil {
+		return nil, err
+	}
+	err = savePublicKey(privateKey.Public(), PublicKeyFileName, nil, keyManager.store)
+	if err != nil {
+		return nil, err
+	}
+	return privateKey, nil
+}
+
+// setKeys switches the keys of the key manager to the saved keys
+func (keyManager *KeyManager) setKeys(keys *activeKeys) {
+	keyManager.mutex.Lock()
+	defer keyManager.mutex.Unlock()
+	keyManager.keyID = keys.keyID
+	keyManager.privateKey = keys.privateKey
+	keyManager.publicKey = keys.publicKey
+	keyManager.retiredKeys = keys.retiredKeys
+}
 
-	return publicKey.(*rsa.PublicKey), nil
+// pruneRetiredKeys deletes the expired retired keys from the store, returning the valid ones
+func (keyManager *KeyManager) pruneRetiredKeys(retiredKeys []*RetiredKey) ([]*RetiredKey, error) {
+	now := time.Now()
+	validKeys := []*RetiredKey{}
+	for _, retiredKey := range retiredKeys {
+		if retiredKey.ExpiresAt.After(now) {
+			validKeys = append(validKeys, retiredKey)
+			continue
+		}
+		if err := keyManager.store.Delete(retiredPublicKeyFileName(retiredKey.KeyID)); err != nil {
+			return nil, err
+		}
+	}
+	return validKeys, nil
 }
 
-func generateKeyFiles(fileLocation string, fs fs.FileSystem) (*rsa.PrivateKey, *rsa.PublicKey, error) {
-	createKeysFolderIfNotExists(fileLocation, fs)
-	privateKey, publicKey, err := generateKeyPair()
+// GenerateNewKeyPair generates a new key pair, retiring the current one for the
+// configured grace period
+func (keyManager *KeyManager) GenerateNewKeyPair() error {
+	return keyManager.RotateKeyPair(keyManager.gracePeriod)
+}
+
+// RotateKeyPair generates a new active key pair and keeps the previous public key
+// valid for verification during the grace period
+func (keyManager *KeyManager) RotateKeyPair(gracePeriod time.Duration) error {
+	keyManager.rotationMutex.Lock()
+	defer keyManager.rotationMutex.Unlock()
+	keyManager.mutex.RLock()
+	currentKeys := &activeKeys{
+		keyID:       keyManager.keyID,
+		privateKey:  keyManager.privateKey,
+		publicKey:   keyManager.publicKey,
+		retiredKeys: keyManager.retiredKeys,
+	}
+	keyManager.mutex.RUnlock()
+	keys, err := keyManager.withKeyStoreLock(func() (*activeKeys, error) {
+		return keyManager.rotateKeyPair(currentKeys, gracePeriod)
+	})
+	if errors.Is(err, ErrKeyStoreConflict) {
+		// Another replica rotated the keys meanwhile, the new keys were not saved so they were
+		// never used and the keys of the store are loaded instead
+		storedKeys, reloadErr := keyManager.withKeyStoreLock(keyManager.reload)
+		if reloadErr != nil {
+			return errors.Join(err, reloadErr)
+		}
+		keyManager.setKeys(storedKeys)
+		return err
+	}
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
-	err = savePrivateKeyToFile(
-		privateKey,
-		fmt.Sprintf("%s/%s", fileLocation, PrivateKeyFileName),
-		fs,
-	)
+	keyManager.setKeys(keys)
+	return nil
+}
+
+// rotateKeyPair saves a new key pair retiring the current key, the key manager only switches
+// to the new keys once they are saved so signing is not blocked while the key is generated
+func (keyManager *KeyManager) rotateKeyPair(currentKeys *activeKeys, gracePeriod time.Duration) (*activeKeys, error) {
+	retiredKeys := currentKeys.retiredKeys
+	if currentKeys.publicKey != nil && gracePeriod > 0 {
+		retiredKey := &RetiredKey{
+			KeyID:     currentKeys.keyID,
+			PublicKey: currentKeys.publicKey,
+			ExpiresAt: time.Now().Add(gracePeriod).UTC().Truncate(time.Second),
+		}
+		err := savePublicKey(
+			retiredKey.PublicKey,
+			retiredPublicKeyFileName(retiredKey.KeyID),
+			map[string]string{
+				ExpiresAtPEMHeader: retiredKey.ExpiresAt.Format(time.RFC3339),
+			},
+			keyManager.store,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to save retired key: %w", err)
+		}
+		retiredKeys = append([]*RetiredKey{retiredKey}, retiredKeys...)
+	}
+	privateKey, err := keyManager.generateKeyFiles()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	err = savePublicKeyToFile(
-		publicKey,
-		fmt.Sprintf("%s/%s", fileLocation, PublicKeyFileName),
-		fs,
-	)
+	retiredKeys, err = keyManager.pruneRetiredKeys(retiredKeys)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	return privateKey, publicKey, nil
+	return newActiveKeys(privateKey, retiredKeys)
 }
 
-// GenerateNewKeyPair generates a new key pair
-func (keyManager *KeyManager) GenerateNewKeyPair() error {
-	privateKey, publicKey, err := generateKeyFiles(keyManager.fileLocation, keyManager.fs)
+// Reload reloads the active and retired keys from the key store, e.g. after an
+// operator replaced them
+func (keyManager *KeyManager) Reload() error {
+	keyManager.rotationMutex.Lock()
+	defer keyManager.rotationMutex.Unlock()
+	keys, err := keyManager.withKeyStoreLock(keyManager.reload)
 	if err != nil {
 		return err
 	}
-	keyManager.privateKey = privateKey
-	keyManager.publicKey = publicKey
+	keyManager.setKeys(keys)
 	return nil
 }
 
-// GetRSAPrivateKey gets the RSA private key
-func (keyManager *KeyManager) GetRSAPrivateKey() *rsa.PrivateKey {
+// reload loads the keys of the store, they are validated before replacing any of the
+// current keys so a failed reload keeps them
+func (keyManager *KeyManager) reload() (*activeKeys, error) {
+	privateKey, _, err := loadPrivateKey(keyManager.store, keyManager.passphraseProvider)
+	if err != nil {
+		return nil, err
+	}
+	if !keyManager.algorithm.IsCompatibleWith(privateKey.Public()) {
+		return nil, fmt.Errorf("Reloaded private key is not compatible with the %s algorithm", keyManager.algorithm)
+	}
+	if err := keyManager.checkPublicKey(privateKey.Public()); err != nil {
+		return nil, err
+	}
+	retiredKeys, err := loadRetiredKeys(keyManager.store)
+	if err != nil {
+		return nil, err
+	}
+	retiredKeys, err = keyManager.pruneRetiredKeys(retiredKeys)
+	if err != nil {
+		return nil, err
+	}
+	return newActiveKeys(privateKey, retiredKeys)
+}
+
+// GetKeyID gets the key ID of the active key
+func (keyManager *KeyManager) GetKeyID() string {
+	keyManager.mutex.RLock()
+	defer keyManager.mutex.RUnlock()
+	return keyManager.keyID
+}
+
+// GetAlgorithm gets the signing algorithm of the active key
+func (keyManager *KeyManager) GetAlgorithm() Algorithm {
+	return keyManager.algorithm
+}
+
+// GetPrivateKey gets the active private key, signers should use GetSigningKey
+// on every signature to use the latest key
+func (keyManager *KeyManager) GetPrivateKey() crypto.Signer {
+	keyManager.mutex.RLock()
+	defer keyManager.mutex.RUnlock()
 	return keyManager.privateKey
 }
 
-// GetRSAPublicKey gets the RSA public key
+// GetSigningKey gets the active private key with its key ID and algorithm
+func (keyManager *KeyManager) GetSigningKey() SigningKey {
+	keyManager.mutex.RLock()
+	defer keyManager.mutex.RUnlock()
+	return SigningKey{
+		KeyID:      keyManager.keyID,
+		PrivateKey: keyManager.privateKey,
+		Algorithm:  keyManager.algorithm,
+	}
+}
+
+// GetRSAPrivateKey gets the RSA private key, nil if the active key is not an RSA key
+func (keyManager *KeyManager) GetRSAPrivateKey() *rsa.PrivateKey {
+	privateKey, _ := keyManager.GetPrivateKey().(*rsa.PrivateKey)
+	return privateKey
+}
+
+// GetRSAPublicKey gets the RSA public key, nil if the active key is not an RSA key
 func (keyManager *KeyManager) GetRSAPublicKey() *rsa.PublicKey {
-	return keyManager.publicKey
+	keyManager.mutex.RLock()
+	defer keyManager.mutex.RUnlock()
+	publicKey, _ := keyManager.publicKey.(*rsa.PublicKey)
+	return publicKey
 }
 
-// GetPublicKey gets the public key
+func (keyManager *KeyManager) verificationKeys() map[string]crypto.PublicKey {
+	publicKeys := map[string]crypto.PublicKey{
+		keyManager.keyID: keyManager.publicKey,
+	}
+	now := time.Now()
+	for _, retiredKey := range keyManager.retiredKeys {
+		if retiredKey.ExpiresAt.After(now) {
+			publicKeys[retiredKey.KeyID] = retiredKey.PublicKey
+		}
+	}
+	return publicKeys
+}
+
+// GetVerificationKeys gets the active and the still valid retired public keys by key ID
+func (keyManager *KeyManager) GetVerificationKeys() map[string]crypto.PublicKey {
+	keyManager.mutex.RLock()
+	defer keyManager.mutex.RUnlock()
+	return keyManager.verificationKeys()
+}
+
+// GetJSONWebKeySet gets the active and the still valid retired public keys as a JSON Web Key Set
+func (keyManager *KeyManager) GetJSONWebKeySet() (*JSONWebKeySet, error) {
+	keyManager.mutex.RLock()
+	defer keyManager.mutex.RUnlock()
+	keySet, err := NewJSONWebKeySet(keyManager.verificationKeys())
+	if err != nil {
+		return nil, err
+	}
+	for index := range keySet.Keys {
+		if keySet.Keys[index].KeyID == keyManager.keyID {
+			keySet.Keys[index].Algorithm = string(keyManager.algorithm)
+		}
+	}
+	return keySet, nil
+}
+
+// GetPublicKey gets the PEM encoded public keys, the active key first followed
+// by the retired keys still within their grace period
 func (keyManager *KeyManager) GetPublicKey(ctx context.Context) (string, error) {
-	publicKeyBytes, err := x509.MarshalPKIXPublicKey(keyManager.publicKey)
+	keyManager.mutex.RLock()
+	defer keyManager.mutex.RUnlock()
+	publicKeyPEM, err := encodePublicKeyToPEM(keyManager.publicKey, nil)
 	if err != nil {
 		return "", fmt.Errorf("Failed to marshal public key: %v", err)
 	}
-	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  PublicKeyType,
-		Bytes: publicKeyBytes,
-	})
+	now := time.Now()
+	for _, retiredKey := range keyManager.retiredKeys {
+		if !retiredKey.ExpiresAt.After(now) {
+			continue
+		}
+		retiredKeyPEM, err := encodePublicKeyToPEM(retiredKey.PublicKey, nil)
+		if err != nil {
+			return "", fmt.Errorf("Failed to marshal retired public key: %v", err)
+		}
+		publicKeyPEM = append(publicKeyPEM, retiredKeyPEM...)
+	}
 	return string(publicKeyPEM), nil
 }
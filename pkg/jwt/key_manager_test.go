@@ -0,0 +1,290 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/log"
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+func signTestToken(t *testing.T, signer TokenSignerer) string {
+	tokenString, err := signer.SignToken(
+		ClaimPair{ExpiryClaim, time.Now().Add(time.Hour)},
+		ClaimPair{TypeClaim, token.AuthTokenType},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *tokenString
+}
+
+func newVerifierFromKeyManager(t *testing.T, keyManager KeyManagerer) TokenVerifierer {
+	publicKey, err := keyManager.GetPublicKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := NewTokenVerifier(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return verifier
+}
+
+func TestKeyManagerRotation(t *testing.T) {
+	t.Run("Rotated_Key_Verifies_During_Grace_Period", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		oldKeyID := keyManager.GetKeyID()
+		oldToken := signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey()))
+
+		err = keyManager.RotateKeyPair(time.Hour)
+		assert.NoError(t, err)
+		assert.NotEqual(t, oldKeyID, keyManager.GetKeyID())
+		assert.Len(t, keyManager.GetVerificationKeys(), 2)
+
+		newToken := signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey()))
+		verifier := newVerifierFromKeyManager(t, keyManager)
+
+		oldJWT, err := verifier.Verify(oldToken)
+		assert.NoError(t, err)
+		assert.Equal(t, oldKeyID, oldJWT.Header[KeyIDHeader])
+		newJWT, err := verifier.Verify(newToken)
+		assert.NoError(t, err)
+		assert.Equal(t, keyManager.GetKeyID(), newJWT.Header[KeyIDHeader])
+	})
+
+	t.Run("Retired_Keys_Are_Reloaded", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		oldToken := signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey()))
+		assert.NoError(t, keyManager.GenerateNewKeyPair())
+
+		reloadedKeyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		assert.Equal(t, keyManager.GetKeyID(), reloadedKeyManager.GetKeyID())
+		assert.Len(t, reloadedKeyManager.GetVerificationKeys(), 2)
+
+		_, err = newVerifierFromKeyManager(t, reloadedKeyManager).Verify(oldToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rotation_Without_Grace_Period", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(keyLocation, WithGracePeriod(0))
+		assert.NoError(t, err)
+		oldToken := signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey()))
+		assert.NoError(t, keyManager.GenerateNewKeyPair())
+
+		assert.Len(t, keyManager.GetVerificationKeys(), 1)
+		_, err = newVerifierFromKeyManager(t, keyManager).Verify(oldToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("Expired_Retired_Keys_Are_Pruned", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		oldKeyID := keyManager.GetKeyID()
+		assert.NoError(t, keyManager.RotateKeyPair(time.Second))
+		time.Sleep(time.Second)
+
+		reloadedKeyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		assert.Len(t, reloadedKeyManager.GetVerificationKeys(), 1)
+		_, err = os.Stat(fmt.Sprintf("%s/%s", keyLocation, retiredPublicKeyFileName(oldKeyID)))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestTokenVerifierWithoutKeyID(t *testing.T) {
+	keyManager, err := NewKeyManager(t.TempDir())
+	assert.NoError(t, err)
+	signer := NewTokenSignerWithKeyProvider(SigningKey{PrivateKey: keyManager.GetPrivateKey(), Algorithm: RS256})
+	legacyToken, err := signer.SignToken(
+		ClaimPair{ExpiryClaim, time.Now().Add(time.Hour)},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, keyManager.GenerateNewKeyPair())
+
+	_, err = newVerifierFromKeyManager(t, keyManager).Verify(*legacyToken)
+	assert.NoError(t, err)
+}
+
+func TestKeyManagerConcurrentStartUp(t *testing.T) {
+	keyLocation := fmt.Sprintf("%s/keys", t.TempDir())
+	keyIDs := make(chan string, 5)
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			keyManager, err := NewKeyManager(keyLocation)
+			if err != nil {
+				errs <- err
+				return
+			}
+			keyIDs <- keyManager.GetKeyID()
+		}()
+	}
+	var firstKeyID string
+	for i := 0; i < 5; i++ {
+		select {
+		case err := <-errs:
+			t.Fatal(err)
+		case keyID := <-keyIDs:
+			if firstKeyID == "" {
+				firstKeyID = keyID
+			}
+			assert.Equal(t, firstKeyID, keyID)
+		}
+	}
+	entries, err := os.ReadDir(keyLocation)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		assert.False(t, strings.HasSuffix(entry.Name(), TemporaryKeyFileSuffix))
+	}
+}
+
+func TestKeyManagerRepairsMismatchedPublicKey(t *testing.T) {
+	keyLocation := t.TempDir()
+	keyManager, err := NewKeyManager(keyLocation)
+	assert.NoError(t, err)
+	otherKeyManager, err := NewKeyManager(t.TempDir())
+	assert.NoError(t, err)
+	otherPublicKey, err := encodePublicKeyToPEM(otherKeyManager.GetRSAPublicKey(), nil)
+	assert.NoError(t, err)
+	publicKeyFileName := fmt.Sprintf("%s/%s", keyLocation, PublicKeyFileName)
+	assert.NoError(t, os.WriteFile(publicKeyFileName, otherPublicKey, PublicKeyFileMode))
+	buffer := &bytes.Buffer{}
+	SetLogger(log.NewLogFactory("development", log.WithOutput(buffer)).NewPackageLogger(LoggerPackageName))
+	defer SetLogger(nil)
+
+	reloadedKeyManager, err := NewKeyManager(keyLocation)
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), "Public key does not match the private key")
+	assert.Contains(t, buffer.String(), otherKeyManager.GetKeyID())
+	assert.Equal(t, keyManager.GetKeyID(), reloadedKeyManager.GetKeyID())
+	assert.True(t, keyManager.GetRSAPublicKey().Equal(reloadedKeyManager.GetRSAPublicKey()))
+	repairedPublicKey, err := loadPublicKey(NewDirectoryKeyStore(keyLocation), PublicKeyFileName)
+	assert.NoError(t, err)
+	assert.True(t, keyManager.GetRSAPublicKey().Equal(repairedPublicKey))
+}
+
+func TestKeyManagerRotationIsSeenByExistingSigners(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	signer := NewTokenSignerFromKeyManager(keyManager)
+	verifier := NewTokenVerifierFromKeyManager(keyManager)
+	oldToken := signTestToken(t, signer)
+
+	assert.NoError(t, keyManager.RotateKeyPair(time.Hour))
+	newToken, err := verifier.Verify(signTestToken(t, signer))
+	assert.NoError(t, err)
+	assert.Equal(t, keyManager.GetKeyID(), newToken.Header[KeyIDHeader])
+	_, err = verifier.Verify(oldToken)
+	assert.NoError(t, err)
+}
+
+func TestKeyManagerConcurrentSigningAndRotation(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()), WithAlgorithm(ES256))
+	assert.NoError(t, err)
+	signer := NewTokenSignerFromKeyManager(keyManager)
+	verifier := NewTokenVerifierFromKeyManager(keyManager)
+
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			for j := 0; j < 20; j++ {
+				tokenString, err := signer.SignToken(ClaimPair{ExpiryClaim, time.Now().Add(time.Hour)})
+				if err == nil {
+					_, err = verifier.Verify(*tokenString)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, keyManager.RotateKeyPair(time.Hour))
+	}
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, <-errs)
+	}
+}
+
+func TestKeyManagerReload(t *testing.T) {
+	keyLocation := t.TempDir()
+	keyManager, err := NewKeyManager(keyLocation)
+	assert.NoError(t, err)
+	otherKeyManager, err := NewKeyManager(keyLocation)
+	assert.NoError(t, err)
+	assert.NoError(t, otherKeyManager.RotateKeyPair(time.Hour))
+	assert.NotEqual(t, otherKeyManager.GetKeyID(), keyManager.GetKeyID())
+
+	assert.NoError(t, keyManager.Reload())
+	assert.Equal(t, otherKeyManager.GetKeyID(), keyManager.GetKeyID())
+	assert.Equal(t, otherKeyManager.GetVerificationKeys(), keyManager.GetVerificationKeys())
+}
+
+func TestKeyManagerReloadIncompatibleKey(t *testing.T) {
+	store := NewMemoryKeyStore()
+	keyManager, err := NewKeyManager("", WithKeyStore(store))
+	assert.NoError(t, err)
+	keyID := keyManager.GetKeyID()
+	verificationKeys := keyManager.GetVerificationKeys()
+	// A replica configured with another algorithm rotates the stored key to its key type
+	_, err = NewKeyManager("", WithKeyStore(store), WithAlgorithm(ES256))
+	assert.NoError(t, err)
+
+	assert.Error(t, keyManager.Reload())
+	assert.Equal(t, keyID, keyManager.GetKeyID())
+	assert.NotNil(t, keyManager.GetRSAPrivateKey())
+	assert.Equal(t, verificationKeys, keyManager.GetVerificationKeys())
+}
+
+func TestKeyManagerWatch(t *testing.T) {
+	t.Run("Reloads_Replaced_Keys", func(t *testing.T) {
+		keyLocation := t.TempDir()
+		keyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		reloadErrs := make(chan error, 1)
+		assert.NoError(t, keyManager.Watch(ctx, func(err error) {
+			reloadErrs <- err
+		}))
+
+		otherKeyManager, err := NewKeyManager(keyLocation)
+		assert.NoError(t, err)
+		assert.NoError(t, otherKeyManager.GenerateNewKeyPair())
+		assert.Eventually(t, func() bool {
+			return keyManager.GetKeyID() == otherKeyManager.GetKeyID()
+		}, 5*time.Second, 10*time.Millisecond)
+		_, err = NewTokenVerifierFromKeyManager(otherKeyManager).Verify(
+			signTestToken(t, NewTokenSignerFromKeyManager(keyManager)),
+		)
+		assert.NoError(t, err)
+		select {
+		case err := <-reloadErrs:
+			t.Fatal(err)
+		default:
+		}
+	})
+
+	t.Run("Unsupported_Key_Store", func(t *testing.T) {
+		keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+		assert.NoError(t, err)
+		assert.Error(t, keyManager.Watch(context.Background(), nil))
+	})
+}
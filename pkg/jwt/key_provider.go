@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"crypto"
+	"fmt"
+)
+
+// SigningKey is a private key with its key ID and signing algorithm
+type SigningKey struct {
+	KeyID      string
+	PrivateKey crypto.Signer
+	Algorithm  Algorithm
+}
+
+// SigningKeyProvider provides the key used to sign tokens, signers get the key on every
+// signature so rotated keys are used straight away
+type SigningKeyProvider interface {
+	GetSigningKey() SigningKey
+}
+
+var (
+	_ SigningKeyProvider = SigningKey{}
+	_ SigningKeyProvider = &KeyManager{}
+)
+
+// GetSigningKey gets the signing key itself, e.g. for a signer with a fixed key
+func (signingKey SigningKey) GetSigningKey() SigningKey {
+	return signingKey
+}
+
+// KeyManagerKeySource is a key source getting the verification keys of a key manager on
+// every verification, so rotated and reloaded keys are used straight away
+type KeyManagerKeySource struct {
+	keyManager KeyManagerer
+}
+
+var _ PublicKeySourcer = &KeyManagerKeySource{}
+
+// NewKeyManagerKeySource creates a key source with the verification keys of the key manager
+func NewKeyManagerKeySource(keyManager KeyManagerer) *KeyManagerKeySource {
+	return &KeyManagerKeySource{
+		keyManager: keyManager,
+	}
+}
+
+// GetPublicKey gets the public key with the given key ID
+func (source *KeyManagerKeySource) GetPublicKey(keyID string) (crypto.PublicKey, error) {
+	publicKey, ok := source.keyManager.GetVerificationKeys()[keyID]
+	if !ok {
+		return nil, fmt.Errorf(unknownKeyIDErrorMessage, keyID)
+	}
+	return publicKey, nil
+}
+
+// GetPublicKeys gets the active and the still valid retired public keys
+func (source *KeyManagerKeySource) GetPublicKeys() (map[string]crypto.PublicKey, error) {
+	return source.keyManager.GetVerificationKeys(), nil
+}
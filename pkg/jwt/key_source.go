@@ -0,0 +1,256 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/quadev-ltd/qd-common/pb/gen/go/pb_authentication"
+)
+
+// Key source constants
+const (
+	DefaultKeyCacheTTL     = 15 * time.Minute
+	DefaultKeyFetchTimeout = 10 * time.Second
+	MinKeyRefreshInterval  = 30 * time.Second
+	// KeyFetchRetryBackoff is the delay before retrying a failed fetch, doubled after each
+	// consecutive failure up to MinKeyRefreshInterval
+	KeyFetchRetryBackoff     = time.Second
+	unknownKeyIDErrorMessage = "Key ID %s is unknown"
+)
+
+// PublicKeySourcer provides the public keys used to verify tokens
+type PublicKeySourcer interface {
+	GetPublicKey(keyID string) (crypto.PublicKey, error)
+	GetPublicKeys() (map[string]crypto.PublicKey, error)
+}
+
+// KeyFetcher fetches the public keys indexed by key ID from a remote source
+type KeyFetcher interface {
+	FetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error)
+}
+
+// StaticKeySource is a key source with a fixed set of public keys
+type StaticKeySource struct {
+	publicKeys map[string]crypto.PublicKey
+}
+
+var _ PublicKeySourcer = &StaticKeySource{}
+
+// NewStaticKeySource creates a key source with a fixed set of public keys
+func NewStaticKeySource(publicKeys map[string]crypto.PublicKey) *StaticKeySource {
+	return &StaticKeySource{
+		publicKeys: publicKeys,
+	}
+}
+
+// GetPublicKey gets the public key with the given key ID
+func (source *StaticKeySource) GetPublicKey(keyID string) (crypto.PublicKey, error) {
+	publicKey, ok := source.publicKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf(unknownKeyIDErrorMessage, keyID)
+	}
+	return publicKey, nil
+}
+
+// GetPublicKeys gets all the public keys
+func (source *StaticKeySource) GetPublicKeys() (map[string]crypto.PublicKey, error) {
+	return source.publicKeys, nil
+}
+
+// CachingKeySource caches the keys of a KeyFetcher, refreshing them when the
+// TTL elapses or when a token references an unknown key ID. A single fetch runs
+// at a time, without holding the lock, so the cached keys are served meanwhile.
+type CachingKeySource struct {
+	fetcher      KeyFetcher
+	ttl          time.Duration
+	fetchTimeout time.Duration
+	mutex        sync.Mutex
+	publicKeys   map[string]crypto.PublicKey
+	fetchedAt    time.Time
+	failedAt     time.Time
+	failures     int
+	fetchErr     error
+	// fetching is closed when the running fetch is done, nil when no fetch is running
+	fetching chan struct{}
+}
+
+var _ PublicKeySourcer = &CachingKeySource{}
+
+// NewCachingKeySource creates a key source caching the fetched keys for the TTL
+func NewCachingKeySource(fetcher KeyFetcher, ttl time.Duration) *CachingKeySource {
+	return &CachingKeySource{
+		fetcher:      fetcher,
+		ttl:          ttl,
+		fetchTimeout: DefaultKeyFetchTimeout,
+	}
+}
+
+// retryBackoff is the delay before the next fetch after the consecutive failures
+func (source *CachingKeySource) retryBackoff() time.Duration {
+	backoff := KeyFetchRetryBackoff
+	for failure := 1; failure < source.failures && backoff < MinKeyRefreshInterval; failure++ {
+		backoff *= 2
+	}
+	return min(backoff, MinKeyRefreshInterval)
+}
+
+// startFetch starts fetching the keys unless a fetch is already running,
+// the returned channel is closed when the fetch is done
+func (source *CachingKeySource) startFetch() chan struct{} {
+	if source.fetching != nil {
+		return source.fetching
+	}
+	done := make(chan struct{})
+	source.fetching = done
+	go source.fetch(done)
+	return done
+}
+
+func (source *CachingKeySource) fetch(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), source.fetchTimeout)
+	defer cancel()
+	publicKeys, err := source.fetcher.FetchKeys(ctx)
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	defer close(done)
+	source.fetching = nil
+	if err != nil {
+		source.failures++
+		source.failedAt = time.Now()
+		source.fetchErr = fmt.Errorf("%w: Failed to fetch public keys: %v", ErrKeysUnavailable, err)
+		return
+	}
+	source.publicKeys = publicKeys
+	source.fetchedAt = time.Now()
+	source.failures = 0
+	source.fetchErr = nil
+}
+
+// refresh fetches the keys, waiting for the fetch without holding the lock when wait is set.
+// The last error is returned without fetching until the retry backoff elapses so the
+// verifications do not queue behind a remote source which is down.
+func (source *CachingKeySource) refresh(wait bool) error {
+	if source.failures > 0 && time.Since(source.failedAt) < source.retryBackoff() {
+		return source.fetchErr
+	}
+	done := source.startFetch()
+	if !wait {
+		return nil
+	}
+	source.mutex.Unlock()
+	<-done
+	source.mutex.Lock()
+	return source.fetchErr
+}
+
+func (source *CachingKeySource) getFreshKeys() (map[string]crypto.PublicKey, error) {
+	if source.publicKeys == nil {
+		if err := source.refresh(true); err != nil {
+			return nil, err
+		}
+	} else if time.Since(source.fetchedAt) >= source.ttl {
+		// The stale keys are served while they are refreshed, or if the remote source is unavailable
+		source.refresh(false)
+	}
+	return source.publicKeys, nil
+}
+
+// GetPublicKey gets the public key with the given key ID, refetching the keys
+// once if the key ID is unknown
+func (source *CachingKeySource) GetPublicKey(keyID string) (crypto.PublicKey, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	publicKeys, err := source.getFreshKeys()
+	if err != nil {
+		return nil, err
+	}
+	if publicKey, ok := publicKeys[keyID]; ok {
+		return publicKey, nil
+	}
+	if time.Since(source.fetchedAt) < MinKeyRefreshInterval {
+		return nil, fmt.Errorf(unknownKeyIDErrorMessage, keyID)
+	}
+	if err := source.refresh(true); err != nil {
+		return nil, err
+	}
+	publicKey, ok := source.publicKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf(unknownKeyIDErrorMessage, keyID)
+	}
+	return publicKey, nil
+}
+
+// GetPublicKeys gets all the cached public keys
+func (source *CachingKeySource) GetPublicKeys() (map[string]crypto.PublicKey, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return source.getFreshKeys()
+}
+
+// JWKSKeyFetcher fetches the public keys from a JSON Web Key Set endpoint
+type JWKSKeyFetcher struct {
+	url        string
+	httpClient *http.Client
+}
+
+var _ KeyFetcher = &JWKSKeyFetcher{}
+
+// NewJWKSKeyFetcher creates a fetcher for the JSON Web Key Set published at the URL
+func NewJWKSKeyFetcher(url string, httpClient *http.Client) *JWKSKeyFetcher {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &JWKSKeyFetcher{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+// FetchKeys fetches the public keys from the JSON Web Key Set endpoint
+func (fetcher *JWKSKeyFetcher) FetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fetcher.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := fetcher.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected JWKS response status: %s", response.Status)
+	}
+	var keySet JSONWebKeySet
+	if err := json.NewDecoder(response.Body).Decode(&keySet); err != nil {
+		return nil, fmt.Errorf("Failed to decode JWKS response: %v", err)
+	}
+	return keySet.PublicKeys()
+}
+
+// GRPCKeyFetcher fetches the public keys through the AuthenticationService GetPublicKey call
+type GRPCKeyFetcher struct {
+	client pb_authentication.AuthenticationServiceClient
+}
+
+var _ KeyFetcher = &GRPCKeyFetcher{}
+
+// NewGRPCKeyFetcher creates a fetcher calling the authentication service
+func NewGRPCKeyFetcher(client pb_authentication.AuthenticationServiceClient) *GRPCKeyFetcher {
+	return &GRPCKeyFetcher{
+		client: client,
+	}
+}
+
+// FetchKeys fetches the PEM encoded public keys from the authentication service
+func (fetcher *GRPCKeyFetcher) FetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	response, err := fetcher.client.GetPublicKey(ctx, &pb_authentication.GetPublicKeyRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return loadPublicKeysFromString(response.PublicKey)
+}
@@ -0,0 +1,275 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/quadev-ltd/qd-common/pb/gen/go/pb_authentication"
+)
+
+type countingKeyFetcher struct {
+	fetcher KeyFetcher
+	calls   int32
+}
+
+func (fetcher *countingKeyFetcher) FetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	atomic.AddInt32(&fetcher.calls, 1)
+	return fetcher.fetcher.FetchKeys(ctx)
+}
+
+type failingKeyFetcher struct {
+	calls int32
+}
+
+func (fetcher *failingKeyFetcher) FetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	atomic.AddInt32(&fetcher.calls, 1)
+	return nil, errors.New("connection refused")
+}
+
+func newJWKSServer(keyManager KeyManagerer) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(JWKSPath, CreateGinJWKSHandler(keyManager))
+	return httptest.NewServer(router)
+}
+
+type testAuthenticationServer struct {
+	pb_authentication.UnimplementedAuthenticationServiceServer
+	keyManager KeyManagerer
+}
+
+func (server *testAuthenticationServer) GetPublicKey(
+	ctx context.Context,
+	_ *pb_authentication.GetPublicKeyRequest,
+) (*pb_authentication.GetPublicKeyResponse, error) {
+	publicKey, err := server.keyManager.GetPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb_authentication.GetPublicKeyResponse{PublicKey: publicKey}, nil
+}
+
+func newBufconnAuthenticationClient(t *testing.T, keyManager KeyManagerer) pb_authentication.AuthenticationServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb_authentication.RegisterAuthenticationServiceServer(server, &testAuthenticationServer{keyManager: keyManager})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { connection.Close() })
+	return pb_authentication.NewAuthenticationServiceClient(connection)
+}
+
+// blockingKeyFetcher returns its keys straight away on the first fetch, then once it is released
+type blockingKeyFetcher struct {
+	publicKeys map[string]crypto.PublicKey
+	calls      int32
+	released   chan struct{}
+}
+
+func (fetcher *blockingKeyFetcher) FetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if atomic.AddInt32(&fetcher.calls, 1) > 1 {
+		<-fetcher.released
+	}
+	return fetcher.publicKeys, nil
+}
+
+// waitForKeyFetch waits for the fetch running in the background to be done
+func waitForKeyFetch(keySource *CachingKeySource) {
+	keySource.mutex.Lock()
+	fetching := keySource.fetching
+	keySource.mutex.Unlock()
+	if fetching != nil {
+		<-fetching
+	}
+}
+
+func TestJSONWebKeySet(t *testing.T) {
+	keyManager, err := NewKeyManager(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, keyManager.GenerateNewKeyPair())
+
+	keySet, err := keyManager.GetJSONWebKeySet()
+	assert.NoError(t, err)
+	publicKeys, err := keySet.PublicKeys()
+
+	assert.NoError(t, err)
+	assert.Equal(t, keyManager.GetVerificationKeys(), publicKeys)
+}
+
+func TestCachingKeySource(t *testing.T) {
+	t.Run("JWKS_Fetcher", func(t *testing.T) {
+		keyManager, err := NewKeyManager(t.TempDir())
+		assert.NoError(t, err)
+		server := newJWKSServer(keyManager)
+		defer server.Close()
+		fetcher := &countingKeyFetcher{fetcher: NewJWKSKeyFetcher(server.URL+JWKSPath, nil)}
+		verifier := NewTokenVerifierWithKeySource(NewCachingKeySource(fetcher, time.Hour))
+
+		_, err = verifier.Verify(signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey())))
+		assert.NoError(t, err)
+		_, err = verifier.Verify(signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey())))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetcher.calls))
+	})
+
+	t.Run("GRPC_Fetcher", func(t *testing.T) {
+		keyManager, err := NewKeyManager(t.TempDir())
+		assert.NoError(t, err)
+		client := newBufconnAuthenticationClient(t, keyManager)
+		verifier := NewTokenVerifierWithKeySource(NewCachingKeySource(NewGRPCKeyFetcher(client), time.Hour))
+
+		_, err = verifier.Verify(signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey())))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unknown_Key_ID_Refetches", func(t *testing.T) {
+		keyManager, err := NewKeyManager(t.TempDir())
+		assert.NoError(t, err)
+		server := newJWKSServer(keyManager)
+		defer server.Close()
+		fetcher := &countingKeyFetcher{fetcher: NewJWKSKeyFetcher(server.URL+JWKSPath, nil)}
+		keySource := NewCachingKeySource(fetcher, time.Hour)
+		verifier := NewTokenVerifierWithKeySource(keySource)
+		_, err = verifier.Verify(signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey())))
+		assert.NoError(t, err)
+
+		assert.NoError(t, keyManager.GenerateNewKeyPair())
+		rotatedToken := signTestToken(t, NewTokenSigner(keyManager.GetRSAPrivateKey()))
+
+		_, err = verifier.Verify(rotatedToken)
+		assert.Error(t, err, "Refetching is throttled right after a fetch")
+
+		keySource.fetchedAt = keySource.fetchedAt.Add(-MinKeyRefreshInterval)
+		_, err = verifier.Verify(rotatedToken)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetcher.calls))
+	})
+
+	t.Run("TTL_Expiry_Refetches", func(t *testing.T) {
+		keyManager, err := NewKeyManager(t.TempDir())
+		assert.NoError(t, err)
+		server := newJWKSServer(keyManager)
+		defer server.Close()
+		fetcher := &countingKeyFetcher{fetcher: NewJWKSKeyFetcher(server.URL+JWKSPath, nil)}
+		keySource := NewCachingKeySource(fetcher, time.Minute)
+
+		_, err = keySource.GetPublicKeys()
+		assert.NoError(t, err)
+		keySource.fetchedAt = keySource.fetchedAt.Add(-time.Minute)
+		_, err = keySource.GetPublicKeys()
+		assert.NoError(t, err)
+		waitForKeyFetch(keySource)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetcher.calls))
+	})
+
+	t.Run("Stale_Keys_Served_When_Fetch_Fails", func(t *testing.T) {
+		keyManager, err := NewKeyManager(t.TempDir())
+		assert.NoError(t, err)
+		server := newJWKSServer(keyManager)
+		keySource := NewCachingKeySource(NewJWKSKeyFetcher(server.URL+JWKSPath, nil), time.Minute)
+		_, err = keySource.GetPublicKeys()
+		assert.NoError(t, err)
+		server.Close()
+
+		keySource.fetchedAt = keySource.fetchedAt.Add(-time.Minute)
+		publicKeys, err := keySource.GetPublicKeys()
+		assert.NoError(t, err)
+		assert.Len(t, publicKeys, 1)
+	})
+
+	t.Run("Failed_Fetches_Are_Backed_Off", func(t *testing.T) {
+		fetcher := &failingKeyFetcher{}
+		keySource := NewCachingKeySource(fetcher, time.Minute)
+
+		for i := 0; i < 10; i++ {
+			_, err := keySource.GetPublicKey("key-id")
+			assert.True(t, errors.Is(err, ErrKeysUnavailable))
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetcher.calls))
+
+		keySource.failedAt = keySource.failedAt.Add(-KeyFetchRetryBackoff)
+		_, err := keySource.GetPublicKeys()
+		assert.True(t, errors.Is(err, ErrKeysUnavailable))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetcher.calls))
+
+		keySource.failedAt = keySource.failedAt.Add(-KeyFetchRetryBackoff)
+		_, err = keySource.GetPublicKeys()
+		assert.True(t, errors.Is(err, ErrKeysUnavailable))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetcher.calls), "The backoff doubles after each failure")
+	})
+
+	t.Run("Stale_Keys_Served_Without_Refetching", func(t *testing.T) {
+		keyManager, err := NewKeyManager(t.TempDir())
+		assert.NoError(t, err)
+		server := newJWKSServer(keyManager)
+		fetcher := &countingKeyFetcher{fetcher: NewJWKSKeyFetcher(server.URL+JWKSPath, nil)}
+		keySource := NewCachingKeySource(fetcher, time.Minute)
+		_, err = keySource.GetPublicKeys()
+		assert.NoError(t, err)
+		server.Close()
+		keySource.fetchedAt = keySource.fetchedAt.Add(-time.Minute)
+
+		for i := 0; i < 10; i++ {
+			publicKeys, err := keySource.GetPublicKeys()
+			assert.NoError(t, err)
+			assert.Len(t, publicKeys, 1)
+			waitForKeyFetch(keySource)
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetcher.calls))
+	})
+
+	t.Run("Cached_Keys_Served_While_Fetching", func(t *testing.T) {
+		keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+		assert.NoError(t, err)
+		fetcher := &blockingKeyFetcher{
+			publicKeys: keyManager.GetVerificationKeys(),
+			released:   make(chan struct{}),
+		}
+		keySource := NewCachingKeySource(fetcher, time.Minute)
+		_, err = keySource.GetPublicKey(keyManager.GetKeyID())
+		assert.NoError(t, err)
+		keySource.fetchedAt = keySource.fetchedAt.Add(-time.Minute)
+
+		verified := make(chan error)
+		go func() {
+			_, err := keySource.GetPublicKey(keyManager.GetKeyID())
+			verified <- err
+		}()
+		go func() {
+			_, err := keySource.GetPublicKey("unknown-key-id")
+			verified <- err
+		}()
+		select {
+		case err := <-verified:
+			assert.NoError(t, err, "The cached key is served while the fetch is blocked")
+		case <-time.After(time.Second):
+			t.Fatal("Cached key lookup blocked behind the fetch")
+		}
+		close(fetcher.released)
+		assert.Error(t, <-verified)
+	})
+}
@@ -0,0 +1,272 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/quadev-ltd/qd-common/pkg/fs"
+)
+
+// Key store errors
+var (
+	ErrKeyNotFound      = errors.New("Key Store: Key not found")
+	ErrReadOnlyKeyStore = errors.New("Key Store: Key store is read only")
+	ErrKeyStoreConflict = errors.New("Key Store: Keys were changed by another process")
+)
+
+// Directory key store constants
+const (
+	KeyDirectoryMode       = 0700
+	PublicKeyFileMode      = 0644
+	LockFileName           = ".lock"
+	TemporaryKeyFileSuffix = ".tmp"
+)
+
+// KeyStore stores the PEM encoded key files of a KeyManager by name,
+// e.g. private.pem, public.pem and the retired public.<kid>.pem keys
+type KeyStore interface {
+	// Read returns ErrKeyNotFound when the key does not exist
+	Read(name string) ([]byte, error)
+	Write(name string, data []byte) error
+	List() ([]string, error)
+	// Delete does not fail when the key does not exist
+	Delete(name string) error
+}
+
+// KeyStoreLocker is implemented by key stores shared between processes, the key manager
+// holds the lock while loading, generating and rotating keys
+type KeyStoreLocker interface {
+	// Lock waits until the lock is acquired and returns the function releasing it, stores
+	// saving the changes made under the lock at once return an error when they are not saved
+	Lock() (func() error, error)
+}
+
+// DirectoryKeyStore stores the keys as files of a directory
+type DirectoryKeyStore struct {
+	location string
+	fs       fs.FileSystem
+}
+
+var (
+	_ KeyStore       = &DirectoryKeyStore{}
+	_ KeyStoreLocker = &DirectoryKeyStore{}
+)
+
+// NewDirectoryKeyStore creates a new directory key store, the directory is created on the first write
+func NewDirectoryKeyStore(location string) *DirectoryKeyStore {
+	return &DirectoryKeyStore{
+		location: location,
+		fs:       &fs.OSFileSystem{},
+	}
+}
+
+func (store *DirectoryKeyStore) path(name string) string {
+	return fmt.Sprintf("%s/%s", store.location, name)
+}
+
+// Read reads a key file
+func (store *DirectoryKeyStore) Read(name string) ([]byte, error) {
+	data, err := store.fs.ReadFile(store.path(name))
+	if err != nil && store.fs.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, store.path(name))
+	}
+	return data, err
+}
+
+func (store *DirectoryKeyStore) createDirectory() error {
+	if _, err := store.fs.Stat(store.location); store.fs.IsNotExist(err) {
+		err := store.fs.Mkdir(store.location, KeyDirectoryMode)
+		// Another process may have created it in the meantime
+		if err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write writes a key file to a temporary file renamed over the key file, so readers
+// and crashes never see a partially written key. Private keys are only readable by the owner.
+func (store *DirectoryKeyStore) Write(name string, data []byte) error {
+	if err := store.createDirectory(); err != nil {
+		return err
+	}
+	var mode os.FileMode = PublicKeyFileMode
+	if name == PrivateKeyFileName {
+		mode = PrivateKeyFileMode
+	}
+	temporaryPath := fmt.Sprintf("%s.%s%s", store.path(name), uuid.New().String(), TemporaryKeyFileSuffix)
+	if err := store.writeTemporaryFile(temporaryPath, data, mode); err != nil {
+		// The temporary file may not exist, the original error is more relevant
+		_ = store.fs.Remove(temporaryPath)
+		return err
+	}
+	if err := store.fs.Rename(temporaryPath, store.path(name)); err != nil {
+		_ = store.fs.Remove(temporaryPath)
+		return err
+	}
+	return nil
+}
+
+func (store *DirectoryKeyStore) writeTemporaryFile(temporaryPath string, data []byte, mode os.FileMode) error {
+	file, err := store.fs.Create(temporaryPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// The mode is restricted before any key material is written
+	if err := file.Chmod(mode); err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Lock takes an exclusive advisory lock on the directory, waiting for other processes
+// holding it, e.g. replicas sharing a volume generating the keys at the same time
+func (store *DirectoryKeyStore) Lock() (func() error, error) {
+	if err := store.createDirectory(); err != nil {
+		return nil, err
+	}
+	return lockFile(store.path(LockFileName))
+}
+
+// List lists the key files of the directory
+func (store *DirectoryKeyStore) List() ([]string, error) {
+	entries, err := store.fs.ReadDir(store.location)
+	if err != nil {
+		if store.fs.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && entry.Name() != LockFileName && !strings.HasSuffix(entry.Name(), TemporaryKeyFileSuffix) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Delete removes a key file
+func (store *DirectoryKeyStore) Delete(name string) error {
+	err := store.fs.Remove(store.path(name))
+	if err != nil && !store.fs.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// MemoryKeyStore keeps the keys in memory, e.g. for tests and short lived processes
+type MemoryKeyStore struct {
+	mutex sync.RWMutex
+	keys  map[string][]byte
+}
+
+var _ KeyStore = &MemoryKeyStore{}
+
+// NewMemoryKeyStore creates a new empty in-memory key store
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{
+		keys: map[string][]byte{},
+	}
+}
+
+// Read reads a key
+func (store *MemoryKeyStore) Read(name string) ([]byte, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	data, exists := store.keys[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return append([]byte{}, data...), nil
+}
+
+// Write writes a key
+func (store *MemoryKeyStore) Write(name string, data []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.keys[name] = append([]byte{}, data...)
+	return nil
+}
+
+// List lists the key names
+func (store *MemoryKeyStore) List() ([]string, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	names := make([]string, 0, len(store.keys))
+	for name := range store.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete deletes a key
+func (store *MemoryKeyStore) Delete(name string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.keys, name)
+	return nil
+}
+
+// PEMKeyStore is a read only key store holding a PEM encoded private key, e.g. injected
+// through an environment variable. The public key is derived from the private key,
+// so key pairs cannot be rotated.
+type PEMKeyStore struct {
+	privateKeyPEM []byte
+}
+
+var _ KeyStore = &PEMKeyStore{}
+
+// NewPEMKeyStore creates a new read only key store with the PEM encoded private key
+func NewPEMKeyStore(privateKeyPEM string) *PEMKeyStore {
+	return &PEMKeyStore{
+		privateKeyPEM: []byte(privateKeyPEM),
+	}
+}
+
+// NewEnvKeyStore creates a new read only key store with the PEM encoded private key
+// of the environment variable
+func NewEnvKeyStore(variableName string) (*PEMKeyStore, error) {
+	privateKeyPEM, exists := os.LookupEnv(variableName)
+	if !exists || privateKeyPEM == "" {
+		return nil, fmt.Errorf("Private key environment variable %s is not set", variableName)
+	}
+	return NewPEMKeyStore(privateKeyPEM), nil
+}
+
+// Read reads the private key, any other key is not found
+func (store *PEMKeyStore) Read(name string) ([]byte, error) {
+	if name != PrivateKeyFileName {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return append([]byte{}, store.privateKeyPEM...), nil
+}
+
+// Write fails as the store is read only
+func (store *PEMKeyStore) Write(name string, data []byte) error {
+	return ErrReadOnlyKeyStore
+}
+
+// List lists the private key
+func (store *PEMKeyStore) List() ([]string, error) {
+	return []string{PrivateKeyFileName}, nil
+}
+
+// Delete fails as the store is read only
+func (store *PEMKeyStore) Delete(name string) error {
+	return ErrReadOnlyKeyStore
+}
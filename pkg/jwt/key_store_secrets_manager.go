@@ -0,0 +1,245 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/google/uuid"
+)
+
+// Version stages of the secret versions
+const (
+	CurrentVersionStage = "AWSCURRENT"
+	PendingVersionStage = "AWSPENDING"
+)
+
+// SecretsManagerClient is the subset of the AWS Secrets Manager client used by the key store,
+// implemented by *secretsmanager.SecretsManager
+type SecretsManagerClient interface {
+	GetSecretValue(input *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error)
+	PutSecretValue(input *secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error)
+	CreateSecret(input *secretsmanager.CreateSecretInput) (*secretsmanager.CreateSecretOutput, error)
+	UpdateSecretVersionStage(
+		input *secretsmanager.UpdateSecretVersionStageInput,
+	) (*secretsmanager.UpdateSecretVersionStageOutput, error)
+}
+
+// SecretsManagerKeyStore stores all the keys in a single AWS Secrets Manager secret
+// as a JSON object of PEM strings by key name. A new version of the secret only becomes
+// current when the version it was read from is still current, so the changes of replicas
+// writing at the same time are rejected with ErrKeyStoreConflict instead of being lost.
+// While the key manager holds the lock, the changes are saved at once on releasing it.
+type SecretsManagerKeyStore struct {
+	// mutex guards the batch, lockMutex serializes the locks of the process
+	mutex     sync.Mutex
+	lockMutex sync.Mutex
+	client    SecretsManagerClient
+	secretID  string
+	batch     *secretVersion
+}
+
+var (
+	_ KeyStore       = &SecretsManagerKeyStore{}
+	_ KeyStoreLocker = &SecretsManagerKeyStore{}
+)
+
+// secretVersion is the keys of a version of the secret
+type secretVersion struct {
+	keys map[string]string
+	// versionID is nil when the secret does not exist
+	versionID *string
+	changed   bool
+}
+
+// NewSecretsManagerKeyStore creates a new AWS Secrets Manager key store, the secret is
+// created on the first write when it does not exist
+func NewSecretsManagerKeyStore(client SecretsManagerClient, secretID string) *SecretsManagerKeyStore {
+	return &SecretsManagerKeyStore{
+		client:   client,
+		secretID: secretID,
+	}
+}
+
+func isSecretsManagerError(err error, code string) bool {
+	var awsError awserr.Error
+	return errors.As(err, &awsError) && awsError.Code() == code
+}
+
+// getVersion gets the keys of the current version of the secret
+func (store *SecretsManagerKeyStore) getVersion() (*secretVersion, error) {
+	output, err := store.client.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(store.secretID),
+	})
+	if err != nil {
+		if isSecretsManagerError(err, secretsmanager.ErrCodeResourceNotFoundException) {
+			return &secretVersion{keys: map[string]string{}}, nil
+		}
+		return nil, fmt.Errorf("Failed to get secret %s: %v", store.secretID, err)
+	}
+	version := &secretVersion{
+		keys:      map[string]string{},
+		versionID: output.VersionId,
+	}
+	if output.SecretString != nil {
+		if err := json.Unmarshal([]byte(*output.SecretString), &version.keys); err != nil {
+			return nil, fmt.Errorf("Secret %s is not a JSON object of keys: %v", store.secretID, err)
+		}
+	}
+	return version, nil
+}
+
+// putVersion saves the keys as the new current version of the secret, failing with
+// ErrKeyStoreConflict when the version they were read from is no longer current
+func (store *SecretsManagerKeyStore) putVersion(version *secretVersion) error {
+	secretString, err := json.Marshal(version.keys)
+	if err != nil {
+		return err
+	}
+	if version.versionID == nil {
+		_, err = store.client.CreateSecret(&secretsmanager.CreateSecretInput{
+			Name:         aws.String(store.secretID),
+			SecretString: aws.String(string(secretString)),
+		})
+		if isSecretsManagerError(err, secretsmanager.ErrCodeResourceExistsException) {
+			return fmt.Errorf("%w: secret %s was created meanwhile", ErrKeyStoreConflict, store.secretID)
+		} else if err != nil {
+			return fmt.Errorf("Failed to create secret %s: %v", store.secretID, err)
+		}
+		return nil
+	}
+	// The new version is pending until it replaces the version it was read from as current
+	output, err := store.client.PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:           aws.String(store.secretID),
+		SecretString:       aws.String(string(secretString)),
+		ClientRequestToken: aws.String(uuid.New().String()),
+		VersionStages:      aws.StringSlice([]string{PendingVersionStage}),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to save secret %s: %v", store.secretID, err)
+	}
+	_, err = store.client.UpdateSecretVersionStage(&secretsmanager.UpdateSecretVersionStageInput{
+		SecretId:            aws.String(store.secretID),
+		VersionStage:        aws.String(CurrentVersionStage),
+		MoveToVersionId:     output.VersionId,
+		RemoveFromVersionId: version.versionID,
+	})
+	if isSecretsManagerError(err, secretsmanager.ErrCodeInvalidParameterException) {
+		return fmt.Errorf("%w: secret %s has a new version: %v", ErrKeyStoreConflict, store.secretID, err)
+	} else if err != nil {
+		return fmt.Errorf("Failed to save secret %s: %v", store.secretID, err)
+	}
+	return nil
+}
+
+// getKeys gets the keys of the locked batch, or of the current version of the secret
+func (store *SecretsManagerKeyStore) getKeys() (map[string]string, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.batch != nil {
+		return maps.Clone(store.batch.keys), nil
+	}
+	version, err := store.getVersion()
+	if err != nil {
+		return nil, err
+	}
+	return version.keys, nil
+}
+
+// updateKeys changes the keys of the locked batch, or saves the change as a new version of the secret,
+// change returns false when the keys are unchanged
+func (store *SecretsManagerKeyStore) updateKeys(change func(keys map[string]string) bool) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.batch != nil {
+		if change(store.batch.keys) {
+			store.batch.changed = true
+		}
+		return nil
+	}
+	version, err := store.getVersion()
+	if err != nil {
+		return err
+	}
+	if !change(version.keys) {
+		return nil
+	}
+	return store.putVersion(version)
+}
+
+// Lock reads the secret once for the key manager, the changes made until the lock is
+// released are saved at once as a new version of the secret
+func (store *SecretsManagerKeyStore) Lock() (func() error, error) {
+	store.lockMutex.Lock()
+	version, err := store.getVersion()
+	if err != nil {
+		store.lockMutex.Unlock()
+		return nil, err
+	}
+	store.mutex.Lock()
+	store.batch = version
+	store.mutex.Unlock()
+	return func() error {
+		defer store.lockMutex.Unlock()
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+		batch := store.batch
+		store.batch = nil
+		if !batch.changed {
+			return nil
+		}
+		return store.putVersion(batch)
+	}, nil
+}
+
+// Read reads a key from the secret
+func (store *SecretsManagerKeyStore) Read(name string) ([]byte, error) {
+	keys, err := store.getKeys()
+	if err != nil {
+		return nil, err
+	}
+	data, exists := keys[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return []byte(data), nil
+}
+
+// Write writes a key to the secret
+func (store *SecretsManagerKeyStore) Write(name string, data []byte) error {
+	return store.updateKeys(func(keys map[string]string) bool {
+		keys[name] = string(data)
+		return true
+	})
+}
+
+// List lists the key names of the secret
+func (store *SecretsManagerKeyStore) List() ([]string, error) {
+	keys, err := store.getKeys()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete deletes a key from the secret
+func (store *SecretsManagerKeyStore) Delete(name string) error {
+	return store.updateKeys(func(keys map[string]string) bool {
+		if _, exists := keys[name]; !exists {
+			return false
+		}
+		delete(keys, name)
+		return true
+	})
+}
@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSecretsManagerClient keeps the versions of the secrets, the AWSCURRENT stage only moves
+// from the version it is attached to
+type stubSecretsManagerClient struct {
+	secrets  map[string]*stubSecret
+	putCalls int
+	// onPut is called once after the next version is put, before it becomes current
+	onPut func()
+	// onCreate is called once before the next secret is created
+	onCreate func()
+}
+
+type stubSecret struct {
+	versions         map[string]string
+	currentVersionID string
+}
+
+func newStubSecretsManagerClient() *stubSecretsManagerClient {
+	return &stubSecretsManagerClient{secrets: map[string]*stubSecret{}}
+}
+
+func (client *stubSecretsManagerClient) GetSecretValue(
+	input *secretsmanager.GetSecretValueInput,
+) (*secretsmanager.GetSecretValueOutput, error) {
+	secret, exists := client.secrets[*input.SecretId]
+	if !exists {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+	}
+	return &secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(secret.versions[secret.currentVersionID]),
+		VersionId:    aws.String(secret.currentVersionID),
+	}, nil
+}
+
+func (client *stubSecretsManagerClient) PutSecretValue(
+	input *secretsmanager.PutSecretValueInput,
+) (*secretsmanager.PutSecretValueOutput, error) {
+	secret, exists := client.secrets[*input.SecretId]
+	if !exists {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+	}
+	client.putCalls++
+	versionID := *input.ClientRequestToken
+	secret.versions[versionID] = *input.SecretString
+	if len(input.VersionStages) == 0 || *input.VersionStages[0] == CurrentVersionStage {
+		secret.currentVersionID = versionID
+	}
+	if onPut := client.onPut; onPut != nil {
+		client.onPut = nil
+		onPut()
+	}
+	return &secretsmanager.PutSecretValueOutput{VersionId: aws.String(versionID)}, nil
+}
+
+func (client *stubSecretsManagerClient) CreateSecret(
+	input *secretsmanager.CreateSecretInput,
+) (*secretsmanager.CreateSecretOutput, error) {
+	if onCreate := client.onCreate; onCreate != nil {
+		client.onCreate = nil
+		onCreate()
+	}
+	if _, exists := client.secrets[*input.Name]; exists {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceExistsException, "secret exists", nil)
+	}
+	client.secrets[*input.Name] = &stubSecret{
+		versions:         map[string]string{"initial-version": *input.SecretString},
+		currentVersionID: "initial-version",
+	}
+	return &secretsmanager.CreateSecretOutput{}, nil
+}
+
+func (client *stubSecretsManagerClient) UpdateSecretVersionStage(
+	input *secretsmanager.UpdateSecretVersionStageInput,
+) (*secretsmanager.UpdateSecretVersionStageOutput, error) {
+	secret, exists := client.secrets[*input.SecretId]
+	if !exists {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+	}
+	if *input.RemoveFromVersionId != secret.currentVersionID {
+		return nil, awserr.New(secretsmanager.ErrCodeInvalidParameterException, "stage is not attached to the version", nil)
+	}
+	secret.currentVersionID = *input.MoveToVersionId
+	return &secretsmanager.UpdateSecretVersionStageOutput{}, nil
+}
+
+func TestKeyStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) KeyStore{
+		"Directory": func(t *testing.T) KeyStore {
+			return NewDirectoryKeyStore(fmt.Sprintf("%s/keys", t.TempDir()))
+		},
+		"Memory": func(t *testing.T) KeyStore {
+			return NewMemoryKeyStore()
+		},
+		"Secrets_Manager": func(t *testing.T) KeyStore {
+			return NewSecretsManagerKeyStore(newStubSecretsManagerClient(), "jwt-keys")
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+
+			_, err := store.Read(PrivateKeyFileName)
+			assert.True(t, errors.Is(err, ErrKeyNotFound))
+			names, err := store.List()
+			assert.NoError(t, err)
+			assert.Empty(t, names)
+
+			assert.NoError(t, store.Write(PrivateKeyFileName, []byte("private")))
+			assert.NoError(t, store.Write(PublicKeyFileName, []byte("public")))
+			data, err := store.Read(PrivateKeyFileName)
+			assert.NoError(t, err)
+			assert.Equal(t, "private", string(data))
+			names, err = store.List()
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{PrivateKeyFileName, PublicKeyFileName}, names)
+
+			assert.NoError(t, store.Delete(PublicKeyFileName))
+			assert.NoError(t, store.Delete(PublicKeyFileName))
+			_, err = store.Read(PublicKeyFileName)
+			assert.True(t, errors.Is(err, ErrKeyNotFound))
+		})
+
+		t.Run(name+"_Key_Manager", func(t *testing.T) {
+			store := newStore(t)
+			keyManager, err := NewKeyManager("", WithKeyStore(store))
+			assert.NoError(t, err)
+			oldToken := signTestToken(t, NewTokenSignerFromKeyManager(keyManager))
+			assert.NoError(t, keyManager.RotateKeyPair(time.Hour))
+
+			reloadedKeyManager, err := NewKeyManager("", WithKeyStore(store))
+			assert.NoError(t, err)
+			assert.Equal(t, keyManager.GetKeyID(), reloadedKeyManager.GetKeyID())
+			_, err = newVerifierFromKeyManager(t, reloadedKeyManager).Verify(oldToken)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSecretsManagerKeyStore(t *testing.T) {
+	t.Run("Rotation_Saves_Once", func(t *testing.T) {
+		client := newStubSecretsManagerClient()
+		keyManager, err := NewKeyManager("", WithKeyStore(NewSecretsManagerKeyStore(client, "jwt-keys")))
+		assert.NoError(t, err)
+
+		assert.NoError(t, keyManager.RotateKeyPair(time.Hour))
+		assert.Equal(t, 1, client.putCalls)
+	})
+
+	t.Run("Concurrent_Change_Is_Rejected", func(t *testing.T) {
+		client := newStubSecretsManagerClient()
+		store := NewSecretsManagerKeyStore(client, "jwt-keys")
+		otherStore := NewSecretsManagerKeyStore(client, "jwt-keys")
+		assert.NoError(t, store.Write(PrivateKeyFileName, []byte("private")))
+
+		unlock, err := store.Lock()
+		assert.NoError(t, err)
+		assert.NoError(t, store.Write(PublicKeyFileName, []byte("public")))
+		assert.NoError(t, otherStore.Write(PublicKeyFileName, []byte("other-public")))
+		assert.True(t, errors.Is(unlock(), ErrKeyStoreConflict))
+
+		data, err := otherStore.Read(PublicKeyFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "other-public", string(data))
+	})
+
+	t.Run("Concurrent_Rotation_Reloads_Stored_Keys", func(t *testing.T) {
+		client := newStubSecretsManagerClient()
+		keyManager, err := NewKeyManager("", WithKeyStore(NewSecretsManagerKeyStore(client, "jwt-keys")))
+		assert.NoError(t, err)
+		otherKeyManager, err := NewKeyManager("", WithKeyStore(NewSecretsManagerKeyStore(client, "jwt-keys")))
+		assert.NoError(t, err)
+		keyID := keyManager.GetKeyID()
+		client.onPut = func() {
+			assert.Equal(t, keyID, keyManager.GetKeyID(), "The new key is not used before it is saved")
+			assert.NoError(t, otherKeyManager.RotateKeyPair(time.Hour))
+		}
+
+		err = keyManager.RotateKeyPair(time.Hour)
+
+		assert.True(t, errors.Is(err, ErrKeyStoreConflict))
+		assert.Equal(t, otherKeyManager.GetKeyID(), keyManager.GetKeyID())
+		assert.Equal(t, otherKeyManager.GetVerificationKeys(), keyManager.GetVerificationKeys())
+	})
+
+	t.Run("Concurrent_Start_Up_Loads_Created_Keys", func(t *testing.T) {
+		client := newStubSecretsManagerClient()
+		var otherKeyManager KeyManagerer
+		client.onCreate = func() {
+			var err error
+			otherKeyManager, err = NewKeyManager("", WithKeyStore(NewSecretsManagerKeyStore(client, "jwt-keys")))
+			assert.NoError(t, err)
+		}
+
+		keyManager, err := NewKeyManager("", WithKeyStore(NewSecretsManagerKeyStore(client, "jwt-keys")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, otherKeyManager.GetKeyID(), keyManager.GetKeyID())
+	})
+}
+
+func TestDirectoryKeyStoreFileModes(t *testing.T) {
+	keyLocation := fmt.Sprintf("%s/keys", t.TempDir())
+	_, err := NewKeyManager(keyLocation)
+	assert.NoError(t, err)
+
+	info, err := os.Stat(keyLocation)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(KeyDirectoryMode), info.Mode().Perm())
+	info, err = os.Stat(fmt.Sprintf("%s/%s", keyLocation, PrivateKeyFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(PrivateKeyFileMode), info.Mode().Perm())
+}
+
+func TestPEMKeyStore(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()), WithAlgorithm(ES256))
+	assert.NoError(t, err)
+	privateKeyPEM, err := encodePrivateKeyToPEM(keyManager.GetPrivateKey(), nil)
+	assert.NoError(t, err)
+	t.Setenv("TEST_JWT_PRIVATE_KEY", string(privateKeyPEM))
+
+	store, err := NewEnvKeyStore("TEST_JWT_PRIVATE_KEY")
+	assert.NoError(t, err)
+	pemKeyManager, err := NewKeyManager("", WithKeyStore(store), WithAlgorithm(ES256))
+	assert.NoError(t, err)
+	assert.Equal(t, keyManager.GetKeyID(), pemKeyManager.GetKeyID())
+	_, err = newVerifierFromKeyManager(t, keyManager).Verify(signTestToken(t, NewTokenSignerFromKeyManager(pemKeyManager)))
+	assert.NoError(t, err)
+
+	assert.True(t, errors.Is(pemKeyManager.GenerateNewKeyPair(), ErrReadOnlyKeyStore))
+	_, err = NewEnvKeyStore("TEST_MISSING_JWT_PRIVATE_KEY")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// KeyReloadDelay is the time waited after the last change of the key directory
+// before reloading, so replacing several key files triggers a single reload
+const KeyReloadDelay = 100 * time.Millisecond
+
+// Watch watches the key directory and reloads the keys when they change, e.g. when an
+// operator replaces them. Reload errors are passed to onError, which may be nil, and the
+// previous keys are kept. Watching stops when the context is done. Only key managers
+// with a DirectoryKeyStore can be watched.
+func (keyManager *KeyManager) Watch(ctx context.Context, onError func(error)) error {
+	store, ok := keyManager.store.(*DirectoryKeyStore)
+	if !ok {
+		return fmt.Errorf("Key store %T cannot be watched", keyManager.store)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("Failed to create key watcher: %v", err)
+	}
+	if err := watcher.Add(store.location); err != nil {
+		watcher.Close()
+		return fmt.Errorf("Failed to watch key directory %s: %v", store.location, err)
+	}
+	if onError == nil {
+		onError = func(error) {}
+	}
+	go keyManager.watch(ctx, watcher, onError)
+	return nil
+}
+
+func isKeyFileEvent(event fsnotify.Event) bool {
+	name := filepath.Base(event.Name)
+	if name == LockFileName || strings.HasSuffix(name, TemporaryKeyFileSuffix) {
+		return false
+	}
+	return event.Has(fsnotify.Create) || event.Has(fsnotify.Write) ||
+		event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)
+}
+
+func (keyManager *KeyManager) watch(ctx context.Context, watcher *fsnotify.Watcher, onError func(error)) {
+	defer watcher.Close()
+	reloadTimer := time.NewTimer(KeyReloadDelay)
+	reloadTimer.Stop()
+	defer reloadTimer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if isKeyFileEvent(event) {
+				reloadTimer.Reset(KeyReloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			onError(fmt.Errorf("Key watcher failed: %v", err))
+		case <-reloadTimer.C:
+			if err := keyManager.Reload(); err != nil {
+				onError(fmt.Errorf("Failed to reload keys: %v", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+
+	"github.com/quadev-ltd/qd-common/pkg/config"
+	"github.com/quadev-ltd/qd-common/pkg/log"
+)
+
+// LoggerPackageName is the package name of the logger of the package
+const LoggerPackageName = "jwt"
+
+var (
+	packageLoggerMutex sync.Mutex
+	packageLogger      log.Loggerer
+)
+
+// SetLogger sets the logger used when the context has no logger, e.g. the package logger
+// of the log factory of the service, by default a logger of the default configuration, which
+// a nil logger restores
+func SetLogger(logger log.Loggerer) {
+	packageLoggerMutex.Lock()
+	defer packageLoggerMutex.Unlock()
+	packageLogger = logger
+}
+
+// getPackageLogger gets the logger set with SetLogger, creating the default one once
+func getPackageLogger() log.Loggerer {
+	packageLoggerMutex.Lock()
+	defer packageLoggerMutex.Unlock()
+	if packageLogger == nil {
+		packageLogger = log.NewLogFactory(config.GetEnvironment()).NewPackageLogger(LoggerPackageName)
+	}
+	return packageLogger
+}
+
+// getLogger gets the logger of the context, added by the logger interceptor or middleware,
+// or the logger of the package when there is none
+func getLogger(ctx context.Context) log.Loggerer {
+	if logger, err := log.GetLoggerFromContext(ctx); err == nil {
+		return logger
+	}
+	return getPackageLogger()
+}
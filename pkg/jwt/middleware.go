@@ -26,6 +26,15 @@ type TokenClaims struct {
 	Expiry          time.Time
 	UserID          string
 	HasPaidFeatures bool
+	Issuer          string
+	Audience        []string
+	Subject         string
+	NotBefore       time.Time
+	IssuedAt        time.Time
+	JWTID           string
+	FamilyID        string
+	Roles           []string
+	Scopes          []string
 }
 
 // AddAuthorizationMetadataToContext adds the authorization Bearer token to the context
@@ -35,7 +44,7 @@ func AddAuthorizationMetadataToContext(ctx context.Context, token string) contex
 		existingMD = metadata.New(map[string]string{})
 	}
 	newMD := metadata.New(map[string]string{
-		"authorization": "Bearer " + token,
+		AuthorizationMetadataKey: BearerPrefix + token,
 	})
 	mergedMD := metadata.Join(existingMD, newMD)
 	return metadata.NewOutgoingContext(ctx, mergedMD)
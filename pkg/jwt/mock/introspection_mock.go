@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: introspection.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+// MockTokenIntrospectorer is a mock of TokenIntrospectorer interface.
+type MockTokenIntrospectorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenIntrospectorerMockRecorder
+}
+
+// MockTokenIntrospectorerMockRecorder is the mock recorder for MockTokenIntrospectorer.
+type MockTokenIntrospectorerMockRecorder struct {
+	mock *MockTokenIntrospectorer
+}
+
+// NewMockTokenIntrospectorer creates a new mock instance.
+func NewMockTokenIntrospectorer(ctrl *gomock.Controller) *MockTokenIntrospectorer {
+	mock := &MockTokenIntrospectorer{ctrl: ctrl}
+	mock.recorder = &MockTokenIntrospectorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenIntrospectorer) EXPECT() *MockTokenIntrospectorerMockRecorder {
+	return m.recorder
+}
+
+// Introspect mocks base method.
+func (m *MockTokenIntrospectorer) Introspect(ctx context.Context, token string) (*jwt.IntrospectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx, token)
+	ret0, _ := ret[0].(*jwt.IntrospectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockTokenIntrospectorerMockRecorder) Introspect(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockTokenIntrospectorer)(nil).Introspect), ctx, token)
+}
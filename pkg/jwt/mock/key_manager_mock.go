@@ -6,10 +6,13 @@ package mock
 
 import (
 	context "context"
+	crypto "crypto"
 	rsa "crypto/rsa"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/quadev-ltd/qd-common/pkg/jwt"
 )
 
 // MockKeyManagerer is a mock of KeyManagerer interface.
@@ -49,6 +52,63 @@ func (mr *MockKeyManagererMockRecorder) GenerateNewKeyPair() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateNewKeyPair", reflect.TypeOf((*MockKeyManagerer)(nil).GenerateNewKeyPair))
 }
 
+// GetAlgorithm mocks base method.
+func (m *MockKeyManagerer) GetAlgorithm() jwt.Algorithm {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlgorithm")
+	ret0, _ := ret[0].(jwt.Algorithm)
+	return ret0
+}
+
+// GetAlgorithm indicates an expected call of GetAlgorithm.
+func (mr *MockKeyManagererMockRecorder) GetAlgorithm() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlgorithm", reflect.TypeOf((*MockKeyManagerer)(nil).GetAlgorithm))
+}
+
+// GetJSONWebKeySet mocks base method.
+func (m *MockKeyManagerer) GetJSONWebKeySet() (*jwt.JSONWebKeySet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJSONWebKeySet")
+	ret0, _ := ret[0].(*jwt.JSONWebKeySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJSONWebKeySet indicates an expected call of GetJSONWebKeySet.
+func (mr *MockKeyManagererMockRecorder) GetJSONWebKeySet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJSONWebKeySet", reflect.TypeOf((*MockKeyManagerer)(nil).GetJSONWebKeySet))
+}
+
+// GetKeyID mocks base method.
+func (m *MockKeyManagerer) GetKeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetKeyID indicates an expected call of GetKeyID.
+func (mr *MockKeyManagererMockRecorder) GetKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyID", reflect.TypeOf((*MockKeyManagerer)(nil).GetKeyID))
+}
+
+// GetPrivateKey mocks base method.
+func (m *MockKeyManagerer) GetPrivateKey() crypto.Signer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateKey")
+	ret0, _ := ret[0].(crypto.Signer)
+	return ret0
+}
+
+// GetPrivateKey indicates an expected call of GetPrivateKey.
+func (mr *MockKeyManagererMockRecorder) GetPrivateKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateKey", reflect.TypeOf((*MockKeyManagerer)(nil).GetPrivateKey))
+}
+
 // GetPublicKey mocks base method.
 func (m *MockKeyManagerer) GetPublicKey(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -91,3 +151,73 @@ func (mr *MockKeyManagererMockRecorder) GetRSAPublicKey() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRSAPublicKey", reflect.TypeOf((*MockKeyManagerer)(nil).GetRSAPublicKey))
 }
+
+// GetSigningKey mocks base method.
+func (m *MockKeyManagerer) GetSigningKey() jwt.SigningKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigningKey")
+	ret0, _ := ret[0].(jwt.SigningKey)
+	return ret0
+}
+
+// GetSigningKey indicates an expected call of GetSigningKey.
+func (mr *MockKeyManagererMockRecorder) GetSigningKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigningKey", reflect.TypeOf((*MockKeyManagerer)(nil).GetSigningKey))
+}
+
+// GetVerificationKeys mocks base method.
+func (m *MockKeyManagerer) GetVerificationKeys() map[string]crypto.PublicKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerificationKeys")
+	ret0, _ := ret[0].(map[string]crypto.PublicKey)
+	return ret0
+}
+
+// GetVerificationKeys indicates an expected call of GetVerificationKeys.
+func (mr *MockKeyManagererMockRecorder) GetVerificationKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerificationKeys", reflect.TypeOf((*MockKeyManagerer)(nil).GetVerificationKeys))
+}
+
+// Reload mocks base method.
+func (m *MockKeyManagerer) Reload() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockKeyManagererMockRecorder) Reload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockKeyManagerer)(nil).Reload))
+}
+
+// RotateKeyPair mocks base method.
+func (m *MockKeyManagerer) RotateKeyPair(gracePeriod time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateKeyPair", gracePeriod)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateKeyPair indicates an expected call of RotateKeyPair.
+func (mr *MockKeyManagererMockRecorder) RotateKeyPair(gracePeriod interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateKeyPair", reflect.TypeOf((*MockKeyManagerer)(nil).RotateKeyPair), gracePeriod)
+}
+
+// Watch mocks base method.
+func (m *MockKeyManagerer) Watch(ctx context.Context, onError func(error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, onError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockKeyManagererMockRecorder) Watch(ctx, onError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockKeyManagerer)(nil).Watch), ctx, onError)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: one_time_token.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/quadev-ltd/qd-common/pkg/jwt"
+	token "github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+// MockConsumedTokenStorer is a mock of ConsumedTokenStorer interface.
+type MockConsumedTokenStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumedTokenStorerMockRecorder
+}
+
+// MockConsumedTokenStorerMockRecorder is the mock recorder for MockConsumedTokenStorer.
+type MockConsumedTokenStorerMockRecorder struct {
+	mock *MockConsumedTokenStorer
+}
+
+// NewMockConsumedTokenStorer creates a new mock instance.
+func NewMockConsumedTokenStorer(ctrl *gomock.Controller) *MockConsumedTokenStorer {
+	mock := &MockConsumedTokenStorer{ctrl: ctrl}
+	mock.recorder = &MockConsumedTokenStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumedTokenStorer) EXPECT() *MockConsumedTokenStorerMockRecorder {
+	return m.recorder
+}
+
+// MarkConsumed mocks base method.
+func (m *MockConsumedTokenStorer) MarkConsumed(ctx context.Context, jwtID string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkConsumed", ctx, jwtID, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkConsumed indicates an expected call of MarkConsumed.
+func (mr *MockConsumedTokenStorerMockRecorder) MarkConsumed(ctx, jwtID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkConsumed", reflect.TypeOf((*MockConsumedTokenStorer)(nil).MarkConsumed), ctx, jwtID, expiresAt)
+}
+
+// MockOneTimeTokenConsumerer is a mock of OneTimeTokenConsumerer interface.
+type MockOneTimeTokenConsumerer struct {
+	ctrl     *gomock.Controller
+	recorder *MockOneTimeTokenConsumererMockRecorder
+}
+
+// MockOneTimeTokenConsumererMockRecorder is the mock recorder for MockOneTimeTokenConsumerer.
+type MockOneTimeTokenConsumererMockRecorder struct {
+	mock *MockOneTimeTokenConsumerer
+}
+
+// NewMockOneTimeTokenConsumerer creates a new mock instance.
+func NewMockOneTimeTokenConsumerer(ctrl *gomock.Controller) *MockOneTimeTokenConsumerer {
+	mock := &MockOneTimeTokenConsumerer{ctrl: ctrl}
+	mock.recorder = &MockOneTimeTokenConsumererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOneTimeTokenConsumerer) EXPECT() *MockOneTimeTokenConsumererMockRecorder {
+	return m.recorder
+}
+
+// VerifyAndConsume mocks base method.
+func (m *MockOneTimeTokenConsumerer) VerifyAndConsume(ctx context.Context, tokenString string, tokenType token.Type) (*jwt.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAndConsume", ctx, tokenString, tokenType)
+	ret0, _ := ret[0].(*jwt.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAndConsume indicates an expected call of VerifyAndConsume.
+func (mr *MockOneTimeTokenConsumererMockRecorder) VerifyAndConsume(ctx, tokenString, tokenType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAndConsume", reflect.TypeOf((*MockOneTimeTokenConsumerer)(nil).VerifyAndConsume), ctx, tokenString, tokenType)
+}
@@ -0,0 +1,162 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: refresh_token_rotator.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+// MockRefreshTokenFamilyStorer is a mock of RefreshTokenFamilyStorer interface.
+type MockRefreshTokenFamilyStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenFamilyStorerMockRecorder
+}
+
+// MockRefreshTokenFamilyStorerMockRecorder is the mock recorder for MockRefreshTokenFamilyStorer.
+type MockRefreshTokenFamilyStorerMockRecorder struct {
+	mock *MockRefreshTokenFamilyStorer
+}
+
+// NewMockRefreshTokenFamilyStorer creates a new mock instance.
+func NewMockRefreshTokenFamilyStorer(ctrl *gomock.Controller) *MockRefreshTokenFamilyStorer {
+	mock := &MockRefreshTokenFamilyStorer{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenFamilyStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenFamilyStorer) EXPECT() *MockRefreshTokenFamilyStorerMockRecorder {
+	return m.recorder
+}
+
+// CreateFamily mocks base method.
+func (m *MockRefreshTokenFamilyStorer) CreateFamily(ctx context.Context, family *jwt.RefreshTokenFamily) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFamily", ctx, family)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFamily indicates an expected call of CreateFamily.
+func (mr *MockRefreshTokenFamilyStorerMockRecorder) CreateFamily(ctx, family interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFamily", reflect.TypeOf((*MockRefreshTokenFamilyStorer)(nil).CreateFamily), ctx, family)
+}
+
+// GetFamily mocks base method.
+func (m *MockRefreshTokenFamilyStorer) GetFamily(ctx context.Context, familyID string) (*jwt.RefreshTokenFamily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFamily", ctx, familyID)
+	ret0, _ := ret[0].(*jwt.RefreshTokenFamily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFamily indicates an expected call of GetFamily.
+func (mr *MockRefreshTokenFamilyStorerMockRecorder) GetFamily(ctx, familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFamily", reflect.TypeOf((*MockRefreshTokenFamilyStorer)(nil).GetFamily), ctx, familyID)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockRefreshTokenFamilyStorer) RevokeFamily(ctx context.Context, familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockRefreshTokenFamilyStorerMockRecorder) RevokeFamily(ctx, familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockRefreshTokenFamilyStorer)(nil).RevokeFamily), ctx, familyID)
+}
+
+// RotateToken mocks base method.
+func (m *MockRefreshTokenFamilyStorer) RotateToken(ctx context.Context, familyID, usedTokenID, newTokenID string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateToken", ctx, familyID, usedTokenID, newTokenID, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateToken indicates an expected call of RotateToken.
+func (mr *MockRefreshTokenFamilyStorerMockRecorder) RotateToken(ctx, familyID, usedTokenID, newTokenID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateToken", reflect.TypeOf((*MockRefreshTokenFamilyStorer)(nil).RotateToken), ctx, familyID, usedTokenID, newTokenID, expiresAt)
+}
+
+// MockRefreshTokenRotatorer is a mock of RefreshTokenRotatorer interface.
+type MockRefreshTokenRotatorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenRotatorerMockRecorder
+}
+
+// MockRefreshTokenRotatorerMockRecorder is the mock recorder for MockRefreshTokenRotatorer.
+type MockRefreshTokenRotatorerMockRecorder struct {
+	mock *MockRefreshTokenRotatorer
+}
+
+// NewMockRefreshTokenRotatorer creates a new mock instance.
+func NewMockRefreshTokenRotatorer(ctrl *gomock.Controller) *MockRefreshTokenRotatorer {
+	mock := &MockRefreshTokenRotatorer{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenRotatorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenRotatorer) EXPECT() *MockRefreshTokenRotatorerMockRecorder {
+	return m.recorder
+}
+
+// RevokeFamily mocks base method.
+func (m *MockRefreshTokenRotatorer) RevokeFamily(ctx context.Context, familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockRefreshTokenRotatorerMockRecorder) RevokeFamily(ctx, familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockRefreshTokenRotatorer)(nil).RevokeFamily), ctx, familyID)
+}
+
+// Rotate mocks base method.
+func (m *MockRefreshTokenRotatorer) Rotate(ctx context.Context, refreshToken string) (*jwt.IssuedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, refreshToken)
+	ret0, _ := ret[0].(*jwt.IssuedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockRefreshTokenRotatorerMockRecorder) Rotate(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockRefreshTokenRotatorer)(nil).Rotate), ctx, refreshToken)
+}
+
+// StartFamily mocks base method.
+func (m *MockRefreshTokenRotatorer) StartFamily(ctx context.Context, email, userID string, hasPaidFeatures bool) (*jwt.IssuedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartFamily", ctx, email, userID, hasPaidFeatures)
+	ret0, _ := ret[0].(*jwt.IssuedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartFamily indicates an expected call of StartFamily.
+func (mr *MockRefreshTokenRotatorerMockRecorder) StartFamily(ctx, email, userID, hasPaidFeatures interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartFamily", reflect.TypeOf((*MockRefreshTokenRotatorer)(nil).StartFamily), ctx, email, userID, hasPaidFeatures)
+}
@@ -0,0 +1,160 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revocation.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevocationStorer is a mock of RevocationStorer interface.
+type MockRevocationStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationStorerMockRecorder
+}
+
+// MockRevocationStorerMockRecorder is the mock recorder for MockRevocationStorer.
+type MockRevocationStorerMockRecorder struct {
+	mock *MockRevocationStorer
+}
+
+// NewMockRevocationStorer creates a new mock instance.
+func NewMockRevocationStorer(ctrl *gomock.Controller) *MockRevocationStorer {
+	mock := &MockRevocationStorer{ctrl: ctrl}
+	mock.recorder = &MockRevocationStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationStorer) EXPECT() *MockRevocationStorerMockRecorder {
+	return m.recorder
+}
+
+// AddRevokedTokenID mocks base method.
+func (m *MockRevocationStorer) AddRevokedTokenID(ctx context.Context, jwtID string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRevokedTokenID", ctx, jwtID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRevokedTokenID indicates an expected call of AddRevokedTokenID.
+func (mr *MockRevocationStorerMockRecorder) AddRevokedTokenID(ctx, jwtID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRevokedTokenID", reflect.TypeOf((*MockRevocationStorer)(nil).AddRevokedTokenID), ctx, jwtID, expiresAt)
+}
+
+// GetUserRevokedBefore mocks base method.
+func (m *MockRevocationStorer) GetUserRevokedBefore(ctx context.Context, userID string) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRevokedBefore", ctx, userID)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRevokedBefore indicates an expected call of GetUserRevokedBefore.
+func (mr *MockRevocationStorerMockRecorder) GetUserRevokedBefore(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRevokedBefore", reflect.TypeOf((*MockRevocationStorer)(nil).GetUserRevokedBefore), ctx, userID)
+}
+
+// IsTokenIDRevoked mocks base method.
+func (m *MockRevocationStorer) IsTokenIDRevoked(ctx context.Context, jwtID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenIDRevoked", ctx, jwtID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenIDRevoked indicates an expected call of IsTokenIDRevoked.
+func (mr *MockRevocationStorerMockRecorder) IsTokenIDRevoked(ctx, jwtID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenIDRevoked", reflect.TypeOf((*MockRevocationStorer)(nil).IsTokenIDRevoked), ctx, jwtID)
+}
+
+// SetUserRevokedBefore mocks base method.
+func (m *MockRevocationStorer) SetUserRevokedBefore(ctx context.Context, userID string, revokedBefore, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRevokedBefore", ctx, userID, revokedBefore, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRevokedBefore indicates an expected call of SetUserRevokedBefore.
+func (mr *MockRevocationStorerMockRecorder) SetUserRevokedBefore(ctx, userID, revokedBefore, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRevokedBefore", reflect.TypeOf((*MockRevocationStorer)(nil).SetUserRevokedBefore), ctx, userID, revokedBefore, expiresAt)
+}
+
+// MockRevokerer is a mock of Revokerer interface.
+type MockRevokerer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevokererMockRecorder
+}
+
+// MockRevokererMockRecorder is the mock recorder for MockRevokerer.
+type MockRevokererMockRecorder struct {
+	mock *MockRevokerer
+}
+
+// NewMockRevokerer creates a new mock instance.
+func NewMockRevokerer(ctrl *gomock.Controller) *MockRevokerer {
+	mock := &MockRevokerer{ctrl: ctrl}
+	mock.recorder = &MockRevokererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevokerer) EXPECT() *MockRevokererMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockRevokerer) IsRevoked(ctx context.Context, jwtID, userID string, issuedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", ctx, jwtID, userID, issuedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockRevokererMockRecorder) IsRevoked(ctx, jwtID, userID, issuedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockRevokerer)(nil).IsRevoked), ctx, jwtID, userID, issuedAt)
+}
+
+// RevokeToken mocks base method.
+func (m *MockRevokerer) RevokeToken(ctx context.Context, jwtID string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, jwtID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockRevokererMockRecorder) RevokeToken(ctx, jwtID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockRevokerer)(nil).RevokeToken), ctx, jwtID, expiresAt)
+}
+
+// RevokeUserTokens mocks base method.
+func (m *MockRevokerer) RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserTokens", ctx, userID, issuedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockRevokererMockRecorder) RevokeUserTokens(ctx, userID, issuedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockRevokerer)(nil).RevokeUserTokens), ctx, userID, issuedBefore)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service_token.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+// MockServiceTokenVerifierer is a mock of ServiceTokenVerifierer interface.
+type MockServiceTokenVerifierer struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceTokenVerifiererMockRecorder
+}
+
+// MockServiceTokenVerifiererMockRecorder is the mock recorder for MockServiceTokenVerifierer.
+type MockServiceTokenVerifiererMockRecorder struct {
+	mock *MockServiceTokenVerifierer
+}
+
+// NewMockServiceTokenVerifierer creates a new mock instance.
+func NewMockServiceTokenVerifierer(ctrl *gomock.Controller) *MockServiceTokenVerifierer {
+	mock := &MockServiceTokenVerifierer{ctrl: ctrl}
+	mock.recorder = &MockServiceTokenVerifiererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceTokenVerifierer) EXPECT() *MockServiceTokenVerifiererMockRecorder {
+	return m.recorder
+}
+
+// VerifyServiceToken mocks base method.
+func (m *MockServiceTokenVerifierer) VerifyServiceToken(tokenString string) (*jwt.ServiceClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyServiceToken", tokenString)
+	ret0, _ := ret[0].(*jwt.ServiceClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyServiceToken indicates an expected call of VerifyServiceToken.
+func (mr *MockServiceTokenVerifiererMockRecorder) VerifyServiceToken(tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyServiceToken", reflect.TypeOf((*MockServiceTokenVerifierer)(nil).VerifyServiceToken), tokenString)
+}
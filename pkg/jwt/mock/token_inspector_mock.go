@@ -37,6 +37,21 @@ func (m *MockTokenInspectorer) EXPECT() *MockTokenInspectorerMockRecorder {
 	return m.recorder
 }
 
+// GetAudienceFromToken mocks base method.
+func (m *MockTokenInspectorer) GetAudienceFromToken(jwtToken *jwt.Token) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudienceFromToken", jwtToken)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudienceFromToken indicates an expected call of GetAudienceFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetAudienceFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudienceFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetAudienceFromToken), jwtToken)
+}
+
 // GetClaimFromToken mocks base method.
 func (m *MockTokenInspectorer) GetClaimFromToken(jwtToken *jwt.Token, claimKey string) (interface{}, error) {
 	m.ctrl.T.Helper()
@@ -112,6 +127,21 @@ func (mr *MockTokenInspectorerMockRecorder) GetExpiryFromToken(jwtToken interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiryFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetExpiryFromToken), jwtToken)
 }
 
+// GetFamilyIDFromToken mocks base method.
+func (m *MockTokenInspectorer) GetFamilyIDFromToken(jwtToken *jwt.Token) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFamilyIDFromToken", jwtToken)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFamilyIDFromToken indicates an expected call of GetFamilyIDFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetFamilyIDFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFamilyIDFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetFamilyIDFromToken), jwtToken)
+}
+
 // GetHasPaidFeaturesFromToken mocks base method.
 func (m *MockTokenInspectorer) GetHasPaidFeaturesFromToken(jwtToken *jwt.Token) (*bool, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +157,111 @@ func (mr *MockTokenInspectorerMockRecorder) GetHasPaidFeaturesFromToken(jwtToken
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHasPaidFeaturesFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetHasPaidFeaturesFromToken), jwtToken)
 }
 
+// GetIssuedAtFromToken mocks base method.
+func (m *MockTokenInspectorer) GetIssuedAtFromToken(jwtToken *jwt.Token) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssuedAtFromToken", jwtToken)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuedAtFromToken indicates an expected call of GetIssuedAtFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetIssuedAtFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuedAtFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetIssuedAtFromToken), jwtToken)
+}
+
+// GetIssuerFromToken mocks base method.
+func (m *MockTokenInspectorer) GetIssuerFromToken(jwtToken *jwt.Token) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssuerFromToken", jwtToken)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuerFromToken indicates an expected call of GetIssuerFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetIssuerFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuerFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetIssuerFromToken), jwtToken)
+}
+
+// GetJWTIDFromToken mocks base method.
+func (m *MockTokenInspectorer) GetJWTIDFromToken(jwtToken *jwt.Token) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJWTIDFromToken", jwtToken)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJWTIDFromToken indicates an expected call of GetJWTIDFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetJWTIDFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJWTIDFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetJWTIDFromToken), jwtToken)
+}
+
+// GetNotBeforeFromToken mocks base method.
+func (m *MockTokenInspectorer) GetNotBeforeFromToken(jwtToken *jwt.Token) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotBeforeFromToken", jwtToken)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotBeforeFromToken indicates an expected call of GetNotBeforeFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetNotBeforeFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotBeforeFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetNotBeforeFromToken), jwtToken)
+}
+
+// GetRolesFromToken mocks base method.
+func (m *MockTokenInspectorer) GetRolesFromToken(jwtToken *jwt.Token) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolesFromToken", jwtToken)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolesFromToken indicates an expected call of GetRolesFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetRolesFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolesFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetRolesFromToken), jwtToken)
+}
+
+// GetScopesFromToken mocks base method.
+func (m *MockTokenInspectorer) GetScopesFromToken(jwtToken *jwt.Token) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScopesFromToken", jwtToken)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScopesFromToken indicates an expected call of GetScopesFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetScopesFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScopesFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetScopesFromToken), jwtToken)
+}
+
+// GetSubjectFromToken mocks base method.
+func (m *MockTokenInspectorer) GetSubjectFromToken(jwtToken *jwt.Token) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectFromToken", jwtToken)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectFromToken indicates an expected call of GetSubjectFromToken.
+func (mr *MockTokenInspectorerMockRecorder) GetSubjectFromToken(jwtToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectFromToken", reflect.TypeOf((*MockTokenInspectorer)(nil).GetSubjectFromToken), jwtToken)
+}
+
 // GetTypeFromToken mocks base method.
 func (m *MockTokenInspectorer) GetTypeFromToken(jwtToken *jwt.Token) (*token.Type, error) {
 	m.ctrl.T.Helper()
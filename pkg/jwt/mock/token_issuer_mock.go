@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: token_issuer.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/quadev-ltd/qd-common/pkg/jwt"
+)
+
+// MockTokenIssuerer is a mock of TokenIssuerer interface.
+type MockTokenIssuerer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenIssuererMockRecorder
+}
+
+// MockTokenIssuererMockRecorder is the mock recorder for MockTokenIssuerer.
+type MockTokenIssuererMockRecorder struct {
+	mock *MockTokenIssuerer
+}
+
+// NewMockTokenIssuerer creates a new mock instance.
+func NewMockTokenIssuerer(ctrl *gomock.Controller) *MockTokenIssuerer {
+	mock := &MockTokenIssuerer{ctrl: ctrl}
+	mock.recorder = &MockTokenIssuererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenIssuerer) EXPECT() *MockTokenIssuererMockRecorder {
+	return m.recorder
+}
+
+// IssueAuthToken mocks base method.
+func (m *MockTokenIssuerer) IssueAuthToken(email, userID string, hasPaidFeatures bool, extraClaims ...jwt.ClaimPair) (*jwt.IssuedToken, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{email, userID, hasPaidFeatures}
+	for _, a := range extraClaims {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IssueAuthToken", varargs...)
+	ret0, _ := ret[0].(*jwt.IssuedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueAuthToken indicates an expected call of IssueAuthToken.
+func (mr *MockTokenIssuererMockRecorder) IssueAuthToken(email, userID, hasPaidFeatures interface{}, extraClaims ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{email, userID, hasPaidFeatures}, extraClaims...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueAuthToken", reflect.TypeOf((*MockTokenIssuerer)(nil).IssueAuthToken), varargs...)
+}
+
+// IssueEmailVerificationToken mocks base method.
+func (m *MockTokenIssuerer) IssueEmailVerificationToken(email, userID string, extraClaims ...jwt.ClaimPair) (*jwt.IssuedToken, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{email, userID}
+	for _, a := range extraClaims {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IssueEmailVerificationToken", varargs...)
+	ret0, _ := ret[0].(*jwt.IssuedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueEmailVerificationToken indicates an expected call of IssueEmailVerificationToken.
+func (mr *MockTokenIssuererMockRecorder) IssueEmailVerificationToken(email, userID interface{}, extraClaims ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{email, userID}, extraClaims...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueEmailVerificationToken", reflect.TypeOf((*MockTokenIssuerer)(nil).IssueEmailVerificationToken), varargs...)
+}
+
+// IssueRefreshToken mocks base method.
+func (m *MockTokenIssuerer) IssueRefreshToken(email, userID string, hasPaidFeatures bool, extraClaims ...jwt.ClaimPair) (*jwt.IssuedToken, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{email, userID, hasPaidFeatures}
+	for _, a := range extraClaims {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IssueRefreshToken", varargs...)
+	ret0, _ := ret[0].(*jwt.IssuedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueRefreshToken indicates an expected call of IssueRefreshToken.
+func (mr *MockTokenIssuererMockRecorder) IssueRefreshToken(email, userID, hasPaidFeatures interface{}, extraClaims ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{email, userID, hasPaidFeatures}, extraClaims...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueRefreshToken", reflect.TypeOf((*MockTokenIssuerer)(nil).IssueRefreshToken), varargs...)
+}
+
+// IssueResetPasswordToken mocks base method.
+func (m *MockTokenIssuerer) IssueResetPasswordToken(email, userID string, extraClaims ...jwt.ClaimPair) (*jwt.IssuedToken, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{email, userID}
+	for _, a := range extraClaims {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IssueResetPasswordToken", varargs...)
+	ret0, _ := ret[0].(*jwt.IssuedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueResetPasswordToken indicates an expected call of IssueResetPasswordToken.
+func (mr *MockTokenIssuererMockRecorder) IssueResetPasswordToken(email, userID interface{}, extraClaims ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{email, userID}, extraClaims...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueResetPasswordToken", reflect.TypeOf((*MockTokenIssuerer)(nil).IssueResetPasswordToken), varargs...)
+}
@@ -0,0 +1,62 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: token_source.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTokenSource is a mock of TokenSource interface.
+type MockTokenSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenSourceMockRecorder
+}
+
+// MockTokenSourceMockRecorder is the mock recorder for MockTokenSource.
+type MockTokenSourceMockRecorder struct {
+	mock *MockTokenSource
+}
+
+// NewMockTokenSource creates a new mock instance.
+func NewMockTokenSource(ctrl *gomock.Controller) *MockTokenSource {
+	mock := &MockTokenSource{ctrl: ctrl}
+	mock.recorder = &MockTokenSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenSource) EXPECT() *MockTokenSourceMockRecorder {
+	return m.recorder
+}
+
+// Invalidate mocks base method.
+func (m *MockTokenSource) Invalidate(token string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Invalidate", token)
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockTokenSourceMockRecorder) Invalidate(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockTokenSource)(nil).Invalidate), token)
+}
+
+// Token mocks base method.
+func (m *MockTokenSource) Token(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockTokenSourceMockRecorder) Token(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockTokenSource)(nil).Token), ctx)
+}
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+// ConsumedTokenStorer persists the JWT IDs of the consumed one-time tokens. Entries are only
+// needed until expiresAt, so stores such as Redis or Mongo can rely on key expiry or TTL indexes.
+type ConsumedTokenStorer interface {
+	// MarkConsumed atomically marks the JWT ID as consumed, returning false when it already was,
+	// e.g. with a Redis SET NX or a unique index
+	MarkConsumed(ctx context.Context, jwtID string, expiresAt time.Time) (bool, error)
+}
+
+// OneTimeTokenConsumerer verifies and consumes one-time tokens
+type OneTimeTokenConsumerer interface {
+	VerifyAndConsume(ctx context.Context, tokenString string, tokenType token.Type) (*TokenClaims, error)
+}
+
+// OneTimeTokenConsumer consumes one-time tokens such as the email verification and
+// reset password tokens, so each token can only be used once
+type OneTimeTokenConsumer struct {
+	tokenVerifier  TokenVerifierer
+	tokenInspector TokenInspectorer
+	store          ConsumedTokenStorer
+}
+
+var _ OneTimeTokenConsumerer = &OneTimeTokenConsumer{}
+
+// NewOneTimeTokenConsumer creates a new one-time token consumer
+func NewOneTimeTokenConsumer(tokenVerifier TokenVerifierer, store ConsumedTokenStorer) *OneTimeTokenConsumer {
+	return &OneTimeTokenConsumer{
+		tokenVerifier:  tokenVerifier,
+		tokenInspector: &TokenInspector{},
+		store:          store,
+	}
+}
+
+// VerifyAndConsume verifies the token is of the type and consumes it, a token that
+// was already consumed returns ErrTokenConsumed and an expired token ErrTokenExpired
+// even within the leeway of the verifier
+func (consumer *OneTimeTokenConsumer) VerifyAndConsume(
+	ctx context.Context,
+	tokenString string,
+	tokenType token.Type,
+) (*TokenClaims, error) {
+	jwtToken, err := consumer.tokenVerifier.Verify(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := consumer.tokenInspector.GetClaimsFromToken(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != tokenType {
+		return nil, fmt.Errorf("%w: %s is not a %s", ErrWrongTokenType, claims.Type, tokenType)
+	}
+	if claims.JWTID == "" {
+		return nil, &MissingClaimError{Name: JWTIDClaim}
+	}
+	// The consumed JWT IDs are only kept until the expiry, so the leeway of the verifier
+	// does not apply or the token could be consumed again once its JWT ID is dropped
+	if !claims.Expiry.After(time.Now()) {
+		return nil, ErrTokenExpired
+	}
+	consumed, err := consumer.store.MarkConsumed(ctx, claims.JWTID, claims.Expiry)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to consume token: %v", err)
+	}
+	if !consumed {
+		return nil, ErrTokenConsumed
+	}
+	return claims, nil
+}
+
+// MemoryConsumedTokenStore keeps the consumed JWT IDs in memory until they expire
+type MemoryConsumedTokenStore struct {
+	mutex            sync.Mutex
+	consumedTokenIDs map[string]time.Time
+}
+
+var _ ConsumedTokenStorer = &MemoryConsumedTokenStore{}
+
+// NewMemoryConsumedTokenStore creates a new in-memory consumed token store
+func NewMemoryConsumedTokenStore() *MemoryConsumedTokenStore {
+	return &MemoryConsumedTokenStore{
+		consumedTokenIDs: map[string]time.Time{},
+	}
+}
+
+func (store *MemoryConsumedTokenStore) removeExpired(now time.Time) {
+	for jwtID, expiresAt := range store.consumedTokenIDs {
+		if !expiresAt.After(now) {
+			delete(store.consumedTokenIDs, jwtID)
+		}
+	}
+}
+
+// MarkConsumed marks the JWT ID as consumed, false when it already was
+func (store *MemoryConsumedTokenStore) MarkConsumed(ctx context.Context, jwtID string, expiresAt time.Time) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	now := time.Now()
+	store.removeExpired(now)
+	if _, exists := store.consumedTokenIDs[jwtID]; exists {
+		return false, nil
+	}
+	store.consumedTokenIDs[jwtID] = expiresAt
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+func TestOneTimeTokenConsumer(t *testing.T) {
+	ctx := context.Background()
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	issuer := NewTokenIssuer(NewTokenSignerFromKeyManager(keyManager))
+	verifier := NewTokenVerifierFromKeyManager(keyManager)
+
+	t.Run("Token_Is_Consumed_Once", func(t *testing.T) {
+		consumer := NewOneTimeTokenConsumer(verifier, NewMemoryConsumedTokenStore())
+		resetToken, err := issuer.IssueResetPasswordToken("test@email.com", "test-user-id")
+		assert.NoError(t, err)
+
+		claims, err := consumer.VerifyAndConsume(ctx, resetToken.Token, token.ResetPasswordTokenType)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-user-id", claims.UserID)
+
+		_, err = consumer.VerifyAndConsume(ctx, resetToken.Token, token.ResetPasswordTokenType)
+		assert.True(t, errors.Is(err, ErrTokenConsumed))
+		assert.Equal(t, ReasonTokenConsumed, GetErrorReason(ToStatusError(err)))
+	})
+
+	t.Run("Concurrent_Consumption", func(t *testing.T) {
+		consumer := NewOneTimeTokenConsumer(verifier, NewMemoryConsumedTokenStore())
+		verificationToken, err := issuer.IssueEmailVerificationToken("test@email.com", "test-user-id")
+		assert.NoError(t, err)
+
+		var waitGroup sync.WaitGroup
+		var mutex sync.Mutex
+		consumed := 0
+		for i := 0; i < 10; i++ {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				_, err := consumer.VerifyAndConsume(ctx, verificationToken.Token, token.EmailVerificationTokenType)
+				if err == nil {
+					mutex.Lock()
+					consumed++
+					mutex.Unlock()
+				}
+			}()
+		}
+		waitGroup.Wait()
+		assert.Equal(t, 1, consumed)
+	})
+
+	t.Run("Wrong_Token_Type", func(t *testing.T) {
+		consumer := NewOneTimeTokenConsumer(verifier, NewMemoryConsumedTokenStore())
+		verificationToken, err := issuer.IssueEmailVerificationToken("test@email.com", "test-user-id")
+		assert.NoError(t, err)
+
+		_, err = consumer.VerifyAndConsume(ctx, verificationToken.Token, token.ResetPasswordTokenType)
+		assert.True(t, errors.Is(err, ErrWrongTokenType))
+		_, err = consumer.VerifyAndConsume(ctx, verificationToken.Token, token.EmailVerificationTokenType)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Replay_Within_Leeway", func(t *testing.T) {
+		consumer := NewOneTimeTokenConsumer(
+			NewTokenVerifierFromKeyManager(keyManager, WithLeeway(time.Minute)),
+			NewMemoryConsumedTokenStore(),
+		)
+		shortIssuer := NewTokenIssuer(
+			NewTokenSignerFromKeyManager(keyManager),
+			WithTokenLifetime(token.ResetPasswordTokenType, time.Second),
+		)
+		resetToken, err := shortIssuer.IssueResetPasswordToken("test@email.com", "test-user-id")
+		assert.NoError(t, err)
+		_, err = consumer.VerifyAndConsume(ctx, resetToken.Token, token.ResetPasswordTokenType)
+		assert.NoError(t, err)
+
+		time.Sleep(time.Until(resetToken.Claims.Expiry) + 100*time.Millisecond)
+
+		_, err = consumer.VerifyAndConsume(ctx, resetToken.Token, token.ResetPasswordTokenType)
+		assert.True(t, errors.Is(err, ErrTokenExpired))
+	})
+
+	t.Run("Invalid_Token", func(t *testing.T) {
+		consumer := NewOneTimeTokenConsumer(verifier, NewMemoryConsumedTokenStore())
+
+		_, err := consumer.VerifyAndConsume(ctx, "invalid.token.value", token.ResetPasswordTokenType)
+		assert.True(t, errors.Is(err, ErrTokenMalformed))
+	})
+}
+
+func TestMemoryConsumedTokenStoreExpiry(t *testing.T) {
+	store := NewMemoryConsumedTokenStore()
+	consumed, err := store.MarkConsumed(context.Background(), "expired-id", time.Now().Add(-time.Second))
+	assert.NoError(t, err)
+	assert.True(t, consumed)
+
+	consumed, err = store.MarkConsumed(context.Background(), "expired-id", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, consumed)
+	assert.Len(t, store.consumedTokenIDs, 1)
+}
@@ -0,0 +1,225 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+// RefreshTokenFamily is the chain of refresh tokens issued from one authentication,
+// only its current token can be exchanged for a new one
+type RefreshTokenFamily struct {
+	FamilyID       string
+	UserID         string
+	CurrentTokenID string
+	Revoked        bool
+	ExpiresAt      time.Time
+}
+
+// RefreshTokenFamilyStorer persists refresh token families. Families are only needed
+// until ExpiresAt, revoked families must be kept until then to keep detecting reuse.
+type RefreshTokenFamilyStorer interface {
+	CreateFamily(ctx context.Context, family *RefreshTokenFamily) error
+	GetFamily(ctx context.Context, familyID string) (*RefreshTokenFamily, error)
+	// RotateToken atomically replaces the current token of the family when it is
+	// usedTokenID, returning false when the family has a different current token
+	RotateToken(ctx context.Context, familyID, usedTokenID, newTokenID string, expiresAt time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+// RefreshTokenRotatorer issues and rotates refresh tokens
+type RefreshTokenRotatorer interface {
+	StartFamily(ctx context.Context, email, userID string, hasPaidFeatures bool) (*IssuedToken, error)
+	Rotate(ctx context.Context, refreshToken string) (*IssuedToken, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+// RefreshTokenRotator rotates refresh tokens, revoking the whole family when a used token is presented
+type RefreshTokenRotator struct {
+	tokenIssuer    TokenIssuerer
+	tokenVerifier  TokenVerifierer
+	tokenInspector TokenInspectorer
+	store          RefreshTokenFamilyStorer
+}
+
+var _ RefreshTokenRotatorer = &RefreshTokenRotator{}
+
+// NewRefreshTokenRotator creates a new refresh token rotator
+func NewRefreshTokenRotator(
+	tokenIssuer TokenIssuerer,
+	tokenVerifier TokenVerifierer,
+	store RefreshTokenFamilyStorer,
+) *RefreshTokenRotator {
+	return &RefreshTokenRotator{
+		tokenIssuer:    tokenIssuer,
+		tokenVerifier:  tokenVerifier,
+		tokenInspector: &TokenInspector{},
+		store:          store,
+	}
+}
+
+// StartFamily issues the first refresh token of a new family
+func (rotator *RefreshTokenRotator) StartFamily(
+	ctx context.Context,
+	email, userID string,
+	hasPaidFeatures bool,
+) (*IssuedToken, error) {
+	familyID := uuid.New().String()
+	issuedToken, err := rotator.tokenIssuer.IssueRefreshToken(
+		email,
+		userID,
+		hasPaidFeatures,
+		ClaimPair{FamilyIDClaim, familyID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = rotator.store.CreateFamily(ctx, &RefreshTokenFamily{
+		FamilyID:       familyID,
+		UserID:         userID,
+		CurrentTokenID: issuedToken.Claims.JWTID,
+		ExpiresAt:      issuedToken.Claims.Expiry,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issuedToken, nil
+}
+
+// Rotate exchanges the current refresh token of a family for a new one. Presenting a
+// token that was already exchanged revokes the family and returns ErrRefreshTokenReused.
+func (rotator *RefreshTokenRotator) Rotate(ctx context.Context, refreshToken string) (*IssuedToken, error) {
+	jwtToken, err := rotator.tokenVerifier.Verify(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := rotator.tokenInspector.GetClaimsFromToken(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != token.RefreshTokenType {
+		return nil, fmt.Errorf("%w: %s is not a refresh token", ErrWrongTokenType, claims.Type)
+	}
+	if claims.FamilyID == "" || claims.JWTID == "" {
+		return nil, errors.New("Refresh Token Rotator: Refresh token has no family")
+	}
+	family, err := rotator.store.GetFamily(ctx, claims.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if family == nil {
+		return nil, ErrRefreshTokenFamilyNotFound
+	}
+	if family.Revoked {
+		return nil, ErrRefreshTokenFamilyRevoked
+	}
+	issuedToken, err := rotator.tokenIssuer.IssueRefreshToken(
+		claims.Email,
+		claims.UserID,
+		claims.HasPaidFeatures,
+		ClaimPair{FamilyIDClaim, claims.FamilyID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := rotator.store.RotateToken(
+		ctx,
+		claims.FamilyID,
+		claims.JWTID,
+		issuedToken.Claims.JWTID,
+		issuedToken.Claims.Expiry,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		if err := rotator.store.RevokeFamily(ctx, claims.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	return issuedToken, nil
+}
+
+// RevokeFamily revokes every refresh token of the family, e.g. on logout
+func (rotator *RefreshTokenRotator) RevokeFamily(ctx context.Context, familyID string) error {
+	return rotator.store.RevokeFamily(ctx, familyID)
+}
+
+// MemoryRefreshTokenFamilyStore keeps the refresh token families in memory until they expire
+type MemoryRefreshTokenFamilyStore struct {
+	mutex    sync.RWMutex
+	families map[string]RefreshTokenFamily
+}
+
+var _ RefreshTokenFamilyStorer = &MemoryRefreshTokenFamilyStore{}
+
+// NewMemoryRefreshTokenFamilyStore creates a new in-memory refresh token family store
+func NewMemoryRefreshTokenFamilyStore() *MemoryRefreshTokenFamilyStore {
+	return &MemoryRefreshTokenFamilyStore{
+		families: map[string]RefreshTokenFamily{},
+	}
+}
+
+func (store *MemoryRefreshTokenFamilyStore) removeExpired(now time.Time) {
+	for familyID, family := range store.families {
+		if !family.ExpiresAt.After(now) {
+			delete(store.families, familyID)
+		}
+	}
+}
+
+// CreateFamily adds a new family
+func (store *MemoryRefreshTokenFamilyStore) CreateFamily(ctx context.Context, family *RefreshTokenFamily) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.removeExpired(time.Now())
+	store.families[family.FamilyID] = *family
+	return nil
+}
+
+// GetFamily gets a copy of the family, nil if it does not exist or expired
+func (store *MemoryRefreshTokenFamilyStore) GetFamily(ctx context.Context, familyID string) (*RefreshTokenFamily, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	family, exists := store.families[familyID]
+	if !exists || !family.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return &family, nil
+}
+
+// RotateToken replaces the current token of the family when it is usedTokenID
+func (store *MemoryRefreshTokenFamilyStore) RotateToken(
+	ctx context.Context,
+	familyID, usedTokenID, newTokenID string,
+	expiresAt time.Time,
+) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	family, exists := store.families[familyID]
+	if !exists || family.Revoked || family.CurrentTokenID != usedTokenID {
+		return false, nil
+	}
+	family.CurrentTokenID = newTokenID
+	family.ExpiresAt = expiresAt
+	store.families[familyID] = family
+	return true, nil
+}
+
+// RevokeFamily marks the family as revoked
+func (store *MemoryRefreshTokenFamilyStore) RevokeFamily(ctx context.Context, familyID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if family, exists := store.families[familyID]; exists {
+		family.Revoked = true
+		store.families[familyID] = family
+	}
+	return nil
+}
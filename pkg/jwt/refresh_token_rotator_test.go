@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRotator(t *testing.T) {
+	ctx := context.Background()
+	keyManager, err := NewKeyManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenIssuer := NewTokenIssuer(NewTokenSignerFromKeyManager(keyManager))
+	verifier := newVerifierFromKeyManager(t, keyManager)
+	newRotator := func() *RefreshTokenRotator {
+		return NewRefreshTokenRotator(tokenIssuer, verifier, NewMemoryRefreshTokenFamilyStore())
+	}
+
+	t.Run("Rotate", func(t *testing.T) {
+		rotator := newRotator()
+		first, err := rotator.StartFamily(ctx, "test@email.com", "test-user-id", true)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, first.Claims.FamilyID)
+
+		second, err := rotator.Rotate(ctx, first.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, first.Claims.FamilyID, second.Claims.FamilyID)
+		assert.NotEqual(t, first.Claims.JWTID, second.Claims.JWTID)
+		assert.Equal(t, "test@email.com", second.Claims.Email)
+		assert.Equal(t, "test-user-id", second.Claims.UserID)
+		assert.True(t, second.Claims.HasPaidFeatures)
+
+		third, err := rotator.Rotate(ctx, second.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, first.Claims.FamilyID, third.Claims.FamilyID)
+	})
+
+	t.Run("Reuse_Revokes_Family", func(t *testing.T) {
+		rotator := newRotator()
+		first, err := rotator.StartFamily(ctx, "test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+		second, err := rotator.Rotate(ctx, first.Token)
+		assert.NoError(t, err)
+
+		_, err = rotator.Rotate(ctx, first.Token)
+		assert.True(t, errors.Is(err, ErrRefreshTokenReused))
+
+		_, err = rotator.Rotate(ctx, second.Token)
+		assert.True(t, errors.Is(err, ErrRefreshTokenFamilyRevoked))
+	})
+
+	t.Run("Revoke_Family", func(t *testing.T) {
+		rotator := newRotator()
+		first, err := rotator.StartFamily(ctx, "test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+		assert.NoError(t, rotator.RevokeFamily(ctx, first.Claims.FamilyID))
+
+		_, err = rotator.Rotate(ctx, first.Token)
+		assert.True(t, errors.Is(err, ErrRefreshTokenFamilyRevoked))
+	})
+
+	t.Run("Unknown_Family", func(t *testing.T) {
+		first, err := newRotator().StartFamily(ctx, "test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+
+		_, err = newRotator().Rotate(ctx, first.Token)
+		assert.True(t, errors.Is(err, ErrRefreshTokenFamilyNotFound))
+	})
+
+	t.Run("Not_A_Refresh_Token", func(t *testing.T) {
+		authToken, err := tokenIssuer.IssueAuthToken("test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+
+		_, err = newRotator().Rotate(ctx, authToken.Token)
+		assert.Error(t, err)
+	})
+}
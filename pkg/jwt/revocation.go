@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultRevocationLeeway is how long the revocations are kept after the tokens expire,
+// covering the leeway of the verifiers accepting tokens after their expiry
+const DefaultRevocationLeeway = 5 * time.Minute
+
+// RevocationStorer persists revoked JWT IDs and per user revocation times.
+// Entries are only needed until expiresAt, so stores such as Redis or Mongo
+// can rely on key expiry or TTL indexes.
+type RevocationStorer interface {
+	AddRevokedTokenID(ctx context.Context, jwtID string, expiresAt time.Time) error
+	IsTokenIDRevoked(ctx context.Context, jwtID string) (bool, error)
+	SetUserRevokedBefore(ctx context.Context, userID string, revokedBefore, expiresAt time.Time) error
+	GetUserRevokedBefore(ctx context.Context, userID string) (*time.Time, error)
+}
+
+// Revokerer revokes tokens before their expiry
+type Revokerer interface {
+	RevokeToken(ctx context.Context, jwtID string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time) error
+	IsRevoked(ctx context.Context, jwtID, userID string, issuedAt time.Time) (bool, error)
+}
+
+// Revoker revokes tokens by JWT ID and by user
+type Revoker struct {
+	store            RevocationStorer
+	maxTokenLifetime time.Duration
+	leeway           time.Duration
+}
+
+var _ Revokerer = &Revoker{}
+
+// RevokerOption configures a Revoker
+type RevokerOption func(*Revoker)
+
+// WithRevocationLeeway sets how long the revocations are kept after the tokens expire,
+// it must not be shorter than the WithLeeway of the verifiers checking the revocations
+func WithRevocationLeeway(leeway time.Duration) RevokerOption {
+	return func(revoker *Revoker) {
+		revoker.leeway = leeway
+	}
+}
+
+// NewRevoker creates a new revoker, maxTokenLifetime bounds how long a user
+// revocation has to be remembered and must be positive
+func NewRevoker(store RevocationStorer, maxTokenLifetime time.Duration, options ...RevokerOption) (*Revoker, error) {
+	if maxTokenLifetime <= 0 {
+		return nil, fmt.Errorf("Revoker: Maximum token lifetime %v is not positive", maxTokenLifetime)
+	}
+	revoker := &Revoker{
+		store:            store,
+		maxTokenLifetime: maxTokenLifetime,
+		leeway:           DefaultRevocationLeeway,
+	}
+	for _, option := range options {
+		option(revoker)
+	}
+	return revoker, nil
+}
+
+// RevokeToken revokes the token with the JWT ID until it expires, plus the leeway
+func (revoker *Revoker) RevokeToken(ctx context.Context, jwtID string, expiresAt time.Time) error {
+	return revoker.store.AddRevokedTokenID(ctx, jwtID, expiresAt.Add(revoker.leeway))
+}
+
+// RevokeUserTokens revokes every token of the user issued before the given time
+func (revoker *Revoker) RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time) error {
+	return revoker.store.SetUserRevokedBefore(
+		ctx,
+		userID,
+		issuedBefore,
+		issuedBefore.Add(revoker.maxTokenLifetime+revoker.leeway),
+	)
+}
+
+// IsRevoked checks whether the token was revoked by its JWT ID or by its user.
+// Issued at times have second precision, so the user revocation time is truncated
+// to the second and tokens issued within the same second are not revoked, a token
+// issued right after the revocation must not be rejected.
+func (revoker *Revoker) IsRevoked(ctx context.Context, jwtID, userID string, issuedAt time.Time) (bool, error) {
+	if jwtID != "" {
+		revoked, err := revoker.store.IsTokenIDRevoked(ctx, jwtID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+	if userID != "" {
+		revokedBefore, err := revoker.store.GetUserRevokedBefore(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		if revokedBefore != nil && issuedAt.Before(revokedBefore.Truncate(time.Second)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type userRevocation struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+// MemoryRevocationStore keeps the revocations in memory until they expire
+type MemoryRevocationStore struct {
+	mutex           sync.RWMutex
+	revokedTokenIDs map[string]time.Time
+	userRevocations map[string]userRevocation
+}
+
+var _ RevocationStorer = &MemoryRevocationStore{}
+
+// NewMemoryRevocationStore creates a new in-memory revocation store
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		revokedTokenIDs: map[string]time.Time{},
+		userRevocations: map[string]userRevocation{},
+	}
+}
+
+func (store *MemoryRevocationStore) removeExpired(now time.Time) {
+	for jwtID, expiresAt := range store.revokedTokenIDs {
+		if !expiresAt.After(now) {
+			delete(store.revokedTokenIDs, jwtID)
+		}
+	}
+	for userID, revocation := range store.userRevocations {
+		if !revocation.expiresAt.After(now) {
+			delete(store.userRevocations, userID)
+		}
+	}
+}
+
+// AddRevokedTokenID adds a revoked JWT ID
+func (store *MemoryRevocationStore) AddRevokedTokenID(ctx context.Context, jwtID string, expiresAt time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.removeExpired(time.Now())
+	store.revokedTokenIDs[jwtID] = expiresAt
+	return nil
+}
+
+// IsTokenIDRevoked checks whether the JWT ID is revoked
+func (store *MemoryRevocationStore) IsTokenIDRevoked(ctx context.Context, jwtID string) (bool, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	expiresAt, exists := store.revokedTokenIDs[jwtID]
+	return exists && expiresAt.After(time.Now()), nil
+}
+
+// SetUserRevokedBefore sets the time before which the user tokens are revoked
+func (store *MemoryRevocationStore) SetUserRevokedBefore(
+	ctx context.Context,
+	userID string,
+	revokedBefore, expiresAt time.Time,
+) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.removeExpired(time.Now())
+	if existing, exists := store.userRevocations[userID]; exists && existing.revokedBefore.After(revokedBefore) {
+		return nil
+	}
+	store.userRevocations[userID] = userRevocation{
+		revokedBefore: revokedBefore,
+		expiresAt:     expiresAt,
+	}
+	return nil
+}
+
+// GetUserRevokedBefore gets the time before which the user tokens are revoked, nil if none
+func (store *MemoryRevocationStore) GetUserRevokedBefore(ctx context.Context, userID string) (*time.Time, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	revocation, exists := store.userRevocations[userID]
+	if !exists || !revocation.expiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return &revocation.revokedBefore, nil
+}
@@ -0,0 +1,164 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRevoker(t *testing.T, store RevocationStorer, maxTokenLifetime time.Duration, options ...RevokerOption) *Revoker {
+	revoker, err := NewRevoker(store, maxTokenLifetime, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return revoker
+}
+
+func TestRevoker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Revoke_Token_ID", func(t *testing.T) {
+		revoker := newTestRevoker(t, NewMemoryRevocationStore(), time.Hour)
+		assert.NoError(t, revoker.RevokeToken(ctx, "revoked-id", time.Now().Add(time.Hour)))
+
+		revoked, err := revoker.IsRevoked(ctx, "revoked-id", "", time.Now())
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		revoked, err = revoker.IsRevoked(ctx, "other-id", "", time.Now())
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Revoked_Token_ID_Expires", func(t *testing.T) {
+		revoker := newTestRevoker(t, NewMemoryRevocationStore(), time.Hour)
+		assert.NoError(t, revoker.RevokeToken(ctx, "revoked-id", time.Now().Add(-DefaultRevocationLeeway-time.Second)))
+
+		revoked, err := revoker.IsRevoked(ctx, "revoked-id", "", time.Now())
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Revoked_Token_ID_Kept_For_Leeway", func(t *testing.T) {
+		revoker := newTestRevoker(t, NewMemoryRevocationStore(), time.Hour)
+		assert.NoError(t, revoker.RevokeToken(ctx, "revoked-id", time.Now().Add(-time.Second)))
+
+		revoked, err := revoker.IsRevoked(ctx, "revoked-id", "", time.Now())
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Non_Positive_Token_Lifetime", func(t *testing.T) {
+		_, err := NewRevoker(NewMemoryRevocationStore(), 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("Revoke_User_Tokens", func(t *testing.T) {
+		revoker := newTestRevoker(t, NewMemoryRevocationStore(), time.Hour)
+		revokedBefore := time.Now()
+		assert.NoError(t, revoker.RevokeUserTokens(ctx, "user-id", revokedBefore))
+
+		revoked, err := revoker.IsRevoked(ctx, "token-id", "user-id", revokedBefore.Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		revoked, err = revoker.IsRevoked(ctx, "token-id", "user-id", revokedBefore.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+		revoked, err = revoker.IsRevoked(ctx, "token-id", "other-user-id", revokedBefore.Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Token_Issued_In_Revocation_Second", func(t *testing.T) {
+		revoker := newTestRevoker(t, NewMemoryRevocationStore(), time.Hour)
+		revokedBefore := time.Now()
+		assert.NoError(t, revoker.RevokeUserTokens(ctx, "user-id", revokedBefore))
+
+		revoked, err := revoker.IsRevoked(ctx, "token-id", "user-id", time.Unix(revokedBefore.Unix(), 0))
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+		revoked, err = revoker.IsRevoked(ctx, "token-id", "user-id", time.Unix(revokedBefore.Unix()-1, 0))
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Earlier_User_Revocation_Does_Not_Override", func(t *testing.T) {
+		store := NewMemoryRevocationStore()
+		revoker := newTestRevoker(t, store, time.Hour)
+		revokedBefore := time.Now()
+		assert.NoError(t, revoker.RevokeUserTokens(ctx, "user-id", revokedBefore))
+		assert.NoError(t, revoker.RevokeUserTokens(ctx, "user-id", revokedBefore.Add(-time.Minute)))
+
+		storedRevokedBefore, err := store.GetUserRevokedBefore(ctx, "user-id")
+		assert.NoError(t, err)
+		assert.Equal(t, revokedBefore, *storedRevokedBefore)
+	})
+}
+
+func TestTokenVerifierWithRevoker(t *testing.T) {
+	ctx := context.Background()
+	keyManager, err := NewKeyManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := keyManager.GetPublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoker := newTestRevoker(t, NewMemoryRevocationStore(), time.Hour)
+	verifier, err := NewTokenVerifier(publicKey, WithRevoker(revoker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewTokenSignerFromKeyManager(keyManager)
+	inspector := &TokenInspector{}
+
+	t.Run("Revoked_Token", func(t *testing.T) {
+		tokenString := signTestToken(t, signer)
+		jwtToken, err := verifier.Verify(tokenString)
+		assert.NoError(t, err)
+		jwtID, err := inspector.GetJWTIDFromToken(jwtToken)
+		assert.NoError(t, err)
+
+		assert.NoError(t, revoker.RevokeToken(ctx, *jwtID, time.Now().Add(time.Hour)))
+
+		_, err = verifier.Verify(tokenString)
+		assert.True(t, errors.Is(err, ErrTokenRevoked))
+	})
+
+	t.Run("Revoked_User", func(t *testing.T) {
+		tokenString, err := signer.SignToken(
+			ClaimPair{IssuedAtClaim, time.Now().Add(-time.Minute)},
+			ClaimPair{ExpiryClaim, time.Now().Add(time.Hour)},
+			ClaimPair{UserIDClaim, "revoked-user-id"},
+		)
+		assert.NoError(t, err)
+		_, err = verifier.Verify(*tokenString)
+		assert.NoError(t, err)
+
+		assert.NoError(t, revoker.RevokeUserTokens(ctx, "revoked-user-id", time.Now()))
+
+		_, err = verifier.Verify(*tokenString)
+		assert.True(t, errors.Is(err, ErrTokenRevoked))
+	})
+
+	t.Run("Revoked_Token_Within_Leeway", func(t *testing.T) {
+		leewayVerifier, err := NewTokenVerifier(publicKey, WithRevoker(revoker), WithLeeway(time.Minute))
+		assert.NoError(t, err)
+		expiry := time.Now().Add(time.Second)
+		tokenString, err := signer.SignToken(ClaimPair{ExpiryClaim, expiry})
+		assert.NoError(t, err)
+		jwtToken, err := leewayVerifier.Verify(*tokenString)
+		assert.NoError(t, err)
+		jwtID, err := inspector.GetJWTIDFromToken(jwtToken)
+		assert.NoError(t, err)
+		assert.NoError(t, revoker.RevokeToken(ctx, *jwtID, expiry))
+
+		time.Sleep(time.Until(expiry) + 100*time.Millisecond)
+
+		_, err = leewayVerifier.Verify(*tokenString)
+		assert.True(t, errors.Is(err, ErrTokenRevoked))
+	})
+}
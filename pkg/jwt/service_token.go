@@ -0,0 +1,248 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+// ServiceClaimsContextKey is the context key of the claims of the verified service token
+const ServiceClaimsContextKey ContextKey = "ContextServiceClaimsKey"
+
+// ServiceClaims are the claims of a service token, the caller is the service that signed it
+type ServiceClaims struct {
+	Caller   string
+	Audience []string
+	Expiry   time.Time
+	JWTID    string
+}
+
+// serviceTokenClaims are the claims identifying the calling service to the target service
+func serviceTokenClaims(serviceName, targetService string) []ClaimPair {
+	return []ClaimPair{
+		{TypeClaim, token.ServiceTokenType},
+		{SubjectClaim, serviceName},
+		{AudienceClaim, []string{targetService}},
+	}
+}
+
+// IssueServiceToken signs a service token with the calling service as subject and the
+// target service as audience, expiring after the lifetime
+func IssueServiceToken(
+	tokenSigner TokenSignerer,
+	serviceName, targetService string,
+	lifetime time.Duration,
+) (*string, error) {
+	claims := append(
+		[]ClaimPair{{ExpiryClaim, time.Now().Add(lifetime)}},
+		serviceTokenClaims(serviceName, targetService)...,
+	)
+	return tokenSigner.SignToken(claims...)
+}
+
+// NewServiceTokenSource creates a token source signing the service tokens of the calls from
+// the service to the target service, each token lasts DefaultServiceTokenLifetime
+func NewServiceTokenSource(
+	tokenSigner TokenSignerer,
+	serviceName, targetService string,
+	options ...TokenSourceOption,
+) *SignedTokenSource {
+	return NewSignedTokenSource(
+		tokenSigner,
+		DefaultServiceTokenLifetime,
+		serviceTokenClaims(serviceName, targetService),
+		options...,
+	)
+}
+
+// ServiceTokenVerifierer verifies the service tokens addressed to a service
+type ServiceTokenVerifierer interface {
+	VerifyServiceToken(tokenString string) (*ServiceClaims, error)
+}
+
+// ServiceKeys maps the names of the services to the sources of the public keys they sign their
+// service tokens with, e.g. a CachingKeySource of their JWKS endpoint, so their rotated keys are
+// trusted without changing the configuration
+type ServiceKeys map[string]PublicKeySourcer
+
+// ServiceTokenVerifier verifies the service tokens addressed to a service
+type ServiceTokenVerifier struct {
+	tokenVerifiers map[string]TokenVerifierer
+	tokenInspector TokenInspectorer
+	serviceName    string
+}
+
+var _ ServiceTokenVerifierer = &ServiceTokenVerifier{}
+
+// NewServiceTokenVerifier creates a new verifier of the service tokens addressed to the service.
+// The token of a caller is only verified with the keys of the key source of the caller in the
+// service keys, so a service cannot sign tokens as another one. The options apply to the
+// verification of the tokens of every caller.
+func NewServiceTokenVerifier(
+	serviceName string,
+	serviceKeys ServiceKeys,
+	options ...TokenVerifierOption,
+) ServiceTokenVerifierer {
+	tokenVerifiers := make(map[string]TokenVerifierer, len(serviceKeys))
+	for caller, keySource := range serviceKeys {
+		tokenVerifiers[caller] = NewTokenVerifierWithKeySource(keySource, options...)
+	}
+	return &ServiceTokenVerifier{
+		tokenVerifiers: tokenVerifiers,
+		tokenInspector: &TokenInspector{},
+		serviceName:    serviceName,
+	}
+}
+
+// VerifyServiceToken verifies the token is a service token addressed to the service,
+// signed with a key of the calling service
+func (verifier *ServiceTokenVerifier) VerifyServiceToken(tokenString string) (*ServiceClaims, error) {
+	unverifiedToken, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	// User tokens are reported as such rather than as tokens of an unknown caller
+	if tokenType, err := verifier.tokenInspector.GetTypeFromToken(unverifiedToken); err == nil &&
+		*tokenType != token.ServiceTokenType {
+		return nil, fmt.Errorf("%w: %s is not a service token", ErrWrongTokenType, *tokenType)
+	}
+	caller, err := verifier.tokenInspector.GetSubjectFromToken(unverifiedToken)
+	if err != nil {
+		return nil, err
+	}
+	tokenVerifier, ok := verifier.tokenVerifiers[*caller]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a known service", ErrSignatureInvalid, *caller)
+	}
+	jwtToken, err := tokenVerifier.Verify(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	tokenType, err := verifier.tokenInspector.GetTypeFromToken(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	if *tokenType != token.ServiceTokenType {
+		return nil, fmt.Errorf("%w: %s is not a service token", ErrWrongTokenType, *tokenType)
+	}
+	audience, err := verifier.tokenInspector.GetAudienceFromToken(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	if !containsAny(audience, verifier.serviceName) {
+		return nil, fmt.Errorf("%w: token is not addressed to %s", ErrInvalidAudience, verifier.serviceName)
+	}
+	expiry, err := verifier.tokenInspector.GetExpiryFromToken(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	claims := &ServiceClaims{
+		Caller:   *caller,
+		Audience: audience,
+		Expiry:   *expiry,
+	}
+	if jwtID, err := verifier.tokenInspector.GetJWTIDFromToken(jwtToken); err == nil {
+		claims.JWTID = *jwtID
+	}
+	return claims, nil
+}
+
+// ServiceAllowlist maps gRPC full method names, or whole services written as
+// "/pb_email.EmailService/*", to the names of the services allowed to call them
+type ServiceAllowlist map[string][]string
+
+// getCallers gets the callers allowed to call the method, false when the method is not in the allowlist
+func (allowlist ServiceAllowlist) getCallers(fullMethod string) ([]string, bool) {
+	if callers, ok := allowlist[fullMethod]; ok {
+		return callers, true
+	}
+	if index := strings.LastIndex(fullMethod, "/"); index > 0 {
+		callers, ok := allowlist[fullMethod[:index]+"/*"]
+		return callers, ok
+	}
+	return nil, false
+}
+
+// GetServiceClaimsFromContext gets the service claims from the context
+func GetServiceClaimsFromContext(ctx context.Context) (*ServiceClaims, error) {
+	if claims, ok := ctx.Value(ServiceClaimsContextKey).(*ServiceClaims); ok {
+		return claims, nil
+	}
+	return nil, fmt.Errorf("Service claims not found in context")
+}
+
+func authenticateServiceContext(
+	ctx context.Context,
+	fullMethod string,
+	verifier ServiceTokenVerifierer,
+	allowlist ServiceAllowlist,
+) (context.Context, error) {
+	callers, ok := allowlist.getCallers(fullMethod)
+	if !ok {
+		return ctx, nil
+	}
+	tokenString, err := GetBearerTokenFromIncomingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := verifier.VerifyServiceToken(tokenString)
+	if err != nil {
+		return nil, toLoggedStatusError(ctx, err)
+	}
+	if !containsAny(callers, claims.Caller) {
+		return nil, status.Errorf(codes.PermissionDenied, "Service %s is not allowed to call %s", claims.Caller, fullMethod)
+	}
+	return context.WithValue(ctx, ServiceClaimsContextKey, claims), nil
+}
+
+// CreateServiceAuthenticationInterceptor is the interceptor that only lets the services of the
+// allowlist call its methods, verifying their service token and adding its claims to the context.
+// Methods not in the allowlist are passed through, the methods in the allowlist must be public
+// in the AuthenticationRules of the user authentication interceptor.
+func CreateServiceAuthenticationInterceptor(
+	verifier ServiceTokenVerifierer,
+	allowlist ServiceAllowlist,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		newCtx, err := authenticateServiceContext(ctx, info.FullMethod, verifier, allowlist)
+		if err != nil {
+			return nil, err
+		}
+		return handler(newCtx, req)
+	}
+}
+
+// CreateServiceAuthenticationStreamInterceptor is the streaming version of CreateServiceAuthenticationInterceptor
+func CreateServiceAuthenticationStreamInterceptor(
+	verifier ServiceTokenVerifierer,
+	allowlist ServiceAllowlist,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		newCtx, err := authenticateServiceContext(stream.Context(), info.FullMethod, verifier, allowlist)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedServerStream{
+			ServerStream: stream,
+			ctx:          newCtx,
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testGatewayService = "gateway-service"
+	testEmailService   = "email-service"
+	testImageService   = "image-analysis-service"
+	testEmailMethod    = "/pb_email.EmailService/SendEmail"
+)
+
+type testServiceKeys struct {
+	keyManagers map[string]KeyManagerer
+	signers     map[string]TokenSignerer
+	serviceKeys ServiceKeys
+}
+
+// newTestServiceKeys creates a key manager per service and the service keys with their key sources
+func newTestServiceKeys(t *testing.T, serviceNames ...string) *testServiceKeys {
+	serviceKeys := &testServiceKeys{
+		keyManagers: map[string]KeyManagerer{},
+		signers:     map[string]TokenSignerer{},
+		serviceKeys: ServiceKeys{},
+	}
+	for _, serviceName := range serviceNames {
+		keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+		assert.NoError(t, err)
+		serviceKeys.keyManagers[serviceName] = keyManager
+		serviceKeys.signers[serviceName] = NewTokenSignerFromKeyManager(keyManager)
+		serviceKeys.serviceKeys[serviceName] = NewKeyManagerKeySource(keyManager)
+	}
+	return serviceKeys
+}
+
+func TestServiceTokenVerifier(t *testing.T) {
+	keys := newTestServiceKeys(t, testGatewayService, testImageService)
+	signer := keys.signers[testGatewayService]
+	verifier := NewServiceTokenVerifier(testEmailService, keys.serviceKeys)
+
+	t.Run("Valid_Service_Token", func(t *testing.T) {
+		tokenString, err := NewServiceTokenSource(signer, testGatewayService, testEmailService).Token(context.Background())
+		assert.NoError(t, err)
+
+		claims, err := verifier.VerifyServiceToken(tokenString)
+		assert.NoError(t, err)
+		assert.Equal(t, testGatewayService, claims.Caller)
+		assert.Equal(t, []string{testEmailService}, claims.Audience)
+		assert.NotEmpty(t, claims.JWTID)
+		assert.WithinDuration(t, time.Now().Add(DefaultServiceTokenLifetime), claims.Expiry, 5*time.Second)
+	})
+
+	t.Run("Other_Audience", func(t *testing.T) {
+		tokenString, err := IssueServiceToken(signer, testGatewayService, testImageService, time.Minute)
+		assert.NoError(t, err)
+
+		_, err = verifier.VerifyServiceToken(*tokenString)
+		assert.True(t, errors.Is(err, ErrInvalidAudience))
+	})
+
+	t.Run("User_Token", func(t *testing.T) {
+		authToken, err := NewTokenIssuer(signer).IssueAuthToken("test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+
+		_, err = verifier.VerifyServiceToken(authToken.Token)
+		assert.True(t, errors.Is(err, ErrWrongTokenType))
+	})
+
+	t.Run("Caller_Impersonated_With_Other_Service_Key", func(t *testing.T) {
+		tokenString, err := IssueServiceToken(keys.signers[testImageService], testGatewayService, testEmailService, time.Minute)
+		assert.NoError(t, err)
+
+		_, err = verifier.VerifyServiceToken(*tokenString)
+		assert.True(t, errors.Is(err, ErrSignatureInvalid))
+	})
+
+	t.Run("Rotated_Caller_Key", func(t *testing.T) {
+		assert.NoError(t, keys.keyManagers[testGatewayService].RotateKeyPair(time.Hour))
+		tokenString, err := IssueServiceToken(signer, testGatewayService, testEmailService, time.Minute)
+		assert.NoError(t, err)
+
+		claims, err := verifier.VerifyServiceToken(*tokenString)
+		assert.NoError(t, err)
+		assert.Equal(t, testGatewayService, claims.Caller)
+	})
+
+	t.Run("Unknown_Caller", func(t *testing.T) {
+		tokenString, err := IssueServiceToken(signer, "unknown-service", testEmailService, time.Minute)
+		assert.NoError(t, err)
+
+		_, err = verifier.VerifyServiceToken(*tokenString)
+		assert.True(t, errors.Is(err, ErrSignatureInvalid))
+	})
+}
+
+func TestServiceAuthenticationInterceptor(t *testing.T) {
+	keys := newTestServiceKeys(t, testGatewayService, testImageService)
+	interceptor := CreateServiceAuthenticationInterceptor(
+		NewServiceTokenVerifier(testEmailService, keys.serviceKeys),
+		ServiceAllowlist{
+			"/pb_email.EmailService/*": {testGatewayService, "authentication-service"},
+		},
+	)
+	var handlerCtx context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCtx = ctx
+		return "response", nil
+	}
+	serviceContext := func(t *testing.T, serviceName string) context.Context {
+		tokenString, err := IssueServiceToken(keys.signers[serviceName], serviceName, testEmailService, time.Minute)
+		assert.NoError(t, err)
+		return contextWithAuthorization(BearerPrefix + *tokenString)
+	}
+
+	t.Run("Allowed_Caller", func(t *testing.T) {
+		_, err := interceptor(serviceContext(t, testGatewayService), "request", &grpc.UnaryServerInfo{FullMethod: testEmailMethod}, handler)
+
+		assert.NoError(t, err)
+		claims, err := GetServiceClaimsFromContext(handlerCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, testGatewayService, claims.Caller)
+	})
+
+	t.Run("Caller_Not_Allowed", func(t *testing.T) {
+		_, err := interceptor(serviceContext(t, testImageService), "request", &grpc.UnaryServerInfo{FullMethod: testEmailMethod}, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Missing_Token", func(t *testing.T) {
+		_, err := interceptor(context.Background(), "request", &grpc.UnaryServerInfo{FullMethod: testEmailMethod}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Caller_Impersonated_With_Other_Service_Key", func(t *testing.T) {
+		tokenString, err := IssueServiceToken(keys.signers[testImageService], testGatewayService, testEmailService, time.Minute)
+		assert.NoError(t, err)
+		ctx := contextWithAuthorization(BearerPrefix + *tokenString)
+
+		_, err = interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testEmailMethod}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Equal(t, ReasonSignatureInvalid, GetErrorReason(err))
+	})
+
+	t.Run("User_Token", func(t *testing.T) {
+		authToken, err := NewTokenIssuer(keys.signers[testGatewayService]).IssueAuthToken("test@email.com", "test-user-id", false)
+		assert.NoError(t, err)
+		ctx := contextWithAuthorization(BearerPrefix + authToken.Token)
+
+		_, err = interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: testEmailMethod}, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, ReasonWrongTokenType, GetErrorReason(err))
+	})
+
+	t.Run("Method_Not_In_Allowlist", func(t *testing.T) {
+		_, err := interceptor(context.Background(), "request", &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+
+		assert.NoError(t, err)
+	})
+}
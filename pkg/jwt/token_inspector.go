@@ -1,7 +1,6 @@
 package jwt
 
 import (
-	"errors"
 	"fmt"
 	"time"
 
@@ -18,6 +17,15 @@ type TokenInspectorer interface {
 	GetTypeFromToken(jwtToken *jwt.Token) (*token.Type, error)
 	GetUserIDFromToken(jwtToken *jwt.Token) (*string, error)
 	GetHasPaidFeaturesFromToken(jwtToken *jwt.Token) (*bool, error)
+	GetIssuerFromToken(jwtToken *jwt.Token) (*string, error)
+	GetAudienceFromToken(jwtToken *jwt.Token) ([]string, error)
+	GetSubjectFromToken(jwtToken *jwt.Token) (*string, error)
+	GetNotBeforeFromToken(jwtToken *jwt.Token) (*time.Time, error)
+	GetIssuedAtFromToken(jwtToken *jwt.Token) (*time.Time, error)
+	GetJWTIDFromToken(jwtToken *jwt.Token) (*string, error)
+	GetFamilyIDFromToken(jwtToken *jwt.Token) (*string, error)
+	GetRolesFromToken(jwtToken *jwt.Token) ([]string, error)
+	GetScopesFromToken(jwtToken *jwt.Token) ([]string, error)
 	GetClaimsFromToken(token *jwt.Token) (*TokenClaims, error)
 	GetClaimsFromTokenString(tokenStr string) (*TokenClaims, error)
 }
@@ -31,23 +39,120 @@ var _ TokenInspectorer = &TokenInspector{}
 func (inspector *TokenInspector) GetClaimFromToken(token *jwt.Token, claimKey string) (interface{}, error) {
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return nil, errors.New("JWT Token claims are not valid")
+		return nil, fmt.Errorf("%w: claims are not a JSON object", ErrTokenMalformed)
 	}
 	return claims[claimKey], nil
 }
 
+// claimError returns a MissingClaimError when the claim is absent and an InvalidClaimError otherwise
+func claimError(value interface{}, claimKey string) error {
+	if value == nil {
+		return &MissingClaimError{Name: claimKey}
+	}
+	return &InvalidClaimError{Name: claimKey}
+}
+
+func (inspector *TokenInspector) getTimeClaimFromToken(jwtToken *jwt.Token, claimKey string) (*time.Time, error) {
+	value, err := inspector.GetClaimFromToken(jwtToken, claimKey)
+	if err != nil {
+		return nil, err
+	}
+	valueTyped, ok := value.(float64)
+	if !ok {
+		return nil, claimError(value, claimKey)
+	}
+	valueTime := time.Unix(int64(valueTyped), 0)
+	return &valueTime, nil
+}
+
+func (inspector *TokenInspector) getStringClaimFromToken(jwtToken *jwt.Token, claimKey string) (*string, error) {
+	value, err := inspector.GetClaimFromToken(jwtToken, claimKey)
+	if err != nil {
+		return nil, err
+	}
+	valueTyped, ok := value.(string)
+	if !ok {
+		return nil, claimError(value, claimKey)
+	}
+	return &valueTyped, nil
+}
+
 // GetExpiryFromToken gets the expiry from a JWT token
 func (inspector *TokenInspector) GetExpiryFromToken(jwtToken *jwt.Token) (*time.Time, error) {
-	expiry, err := inspector.GetClaimFromToken(jwtToken, ExpiryClaim)
+	return inspector.getTimeClaimFromToken(jwtToken, ExpiryClaim)
+}
+
+// GetNotBeforeFromToken gets the not before time from a JWT token
+func (inspector *TokenInspector) GetNotBeforeFromToken(jwtToken *jwt.Token) (*time.Time, error) {
+	return inspector.getTimeClaimFromToken(jwtToken, NotBeforeClaim)
+}
+
+// GetIssuedAtFromToken gets the issued at time from a JWT token
+func (inspector *TokenInspector) GetIssuedAtFromToken(jwtToken *jwt.Token) (*time.Time, error) {
+	return inspector.getTimeClaimFromToken(jwtToken, IssuedAtClaim)
+}
+
+// GetIssuerFromToken gets the issuer from a JWT token
+func (inspector *TokenInspector) GetIssuerFromToken(jwtToken *jwt.Token) (*string, error) {
+	return inspector.getStringClaimFromToken(jwtToken, IssuerClaim)
+}
+
+// GetSubjectFromToken gets the subject from a JWT token
+func (inspector *TokenInspector) GetSubjectFromToken(jwtToken *jwt.Token) (*string, error) {
+	return inspector.getStringClaimFromToken(jwtToken, SubjectClaim)
+}
+
+// GetJWTIDFromToken gets the JWT ID from a JWT token
+func (inspector *TokenInspector) GetJWTIDFromToken(jwtToken *jwt.Token) (*string, error) {
+	return inspector.getStringClaimFromToken(jwtToken, JWTIDClaim)
+}
+
+// GetFamilyIDFromToken gets the refresh token family ID from a JWT token
+func (inspector *TokenInspector) GetFamilyIDFromToken(jwtToken *jwt.Token) (*string, error) {
+	return inspector.getStringClaimFromToken(jwtToken, FamilyIDClaim)
+}
+
+// GetRolesFromToken gets the roles from a JWT token
+func (inspector *TokenInspector) GetRolesFromToken(jwtToken *jwt.Token) ([]string, error) {
+	return inspector.getStringListClaimFromToken(jwtToken, RolesClaim)
+}
+
+// GetScopesFromToken gets the scopes from a JWT token
+func (inspector *TokenInspector) GetScopesFromToken(jwtToken *jwt.Token) ([]string, error) {
+	return inspector.getStringListClaimFromToken(jwtToken, ScopesClaim)
+}
+
+// GetAudienceFromToken gets the audience from a JWT token, which may be a single string or a list
+func (inspector *TokenInspector) GetAudienceFromToken(jwtToken *jwt.Token) ([]string, error) {
+	return inspector.getStringListClaimFromToken(jwtToken, AudienceClaim)
+}
+
+func (inspector *TokenInspector) getStringListClaimFromToken(
+	jwtToken *jwt.Token,
+	claimKey string,
+) ([]string, error) {
+	claim, err := inspector.GetClaimFromToken(jwtToken, claimKey)
 	if err != nil {
 		return nil, err
 	}
-	expiryTyped, ok := expiry.(float64)
-	if !ok {
-		return nil, errors.New("JWT Token expiry is not valid")
+	switch values := claim.(type) {
+	case string:
+		return []string{values}, nil
+	case []string:
+		return values, nil
+	case []interface{}:
+		valueList := make([]string, 0, len(values))
+		for _, value := range values {
+			valueString, ok := value.(string)
+			if !ok {
+				return nil, &InvalidClaimError{Name: claimKey}
+			}
+			valueList = append(valueList, valueString)
+		}
+		return valueList, nil
+	default:
+		return nil, claimError(claim, claimKey)
 	}
-	expiryTime := time.Unix(int64(expiryTyped), 0)
-	return &expiryTime, nil
 }
 
 // GetEmailFromToken gets the email from a JWT token
@@ -58,7 +163,7 @@ func (inspector *TokenInspector) GetEmailFromToken(jwtToken *jwt.Token) (*string
 	}
 	emailTyped, ok := email.(string)
 	if !ok {
-		return nil, errors.New("JWT Token email is not of valid type")
+		return nil, claimError(email, EmailClaim)
 	}
 	return &emailTyped, nil
 }
@@ -71,7 +176,7 @@ func (inspector *TokenInspector) GetTypeFromToken(jwtToken *jwt.Token) (*token.T
 	}
 	typeValueString, ok := typeValue.(string)
 	if !ok {
-		return nil, errors.New("JWT Token type is not of valid type")
+		return nil, claimError(typeValue, TypeClaim)
 	}
 	typeValueTokenType := token.Type(typeValueString)
 	return &typeValueTokenType, nil
@@ -87,7 +192,7 @@ func (inspector *TokenInspector) GetUserIDFromToken(
 	}
 	userID, ok := userIDClaim.(string)
 	if !ok {
-		return nil, errors.New("JWT Token type is not of valid type")
+		return nil, claimError(userIDClaim, UserIDClaim)
 	}
 	return &userID, nil
 }
@@ -100,7 +205,7 @@ func (inspector *TokenInspector) GetHasPaidFeaturesFromToken(jwtToken *jwt.Token
 	}
 	hasPaidFeatures, ok := hasPaidFeaturesClaim.(bool)
 	if !ok {
-		return nil, errors.New("JWT Token hasPaidFeatures claim is not of valid type")
+		return nil, claimError(hasPaidFeaturesClaim, HasPaidFeaturesClaim)
 	}
 	return &hasPaidFeatures, nil
 }
@@ -109,31 +214,107 @@ func (inspector *TokenInspector) GetHasPaidFeaturesFromToken(jwtToken *jwt.Token
 func (inspector *TokenInspector) GetClaimsFromToken(token *jwt.Token) (*TokenClaims, error) {
 	email, err := inspector.GetEmailFromToken(token)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting email from token: %v", err)
+		return nil, fmt.Errorf("Error getting email from token: %w", err)
 	}
 	tokenType, err := inspector.GetTypeFromToken(token)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting type claim from token: %v", err)
+		return nil, fmt.Errorf("Error getting type claim from token: %w", err)
 	}
 	expiry, err := inspector.GetExpiryFromToken(token)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting expiry claim from token: %v", err)
+		return nil, fmt.Errorf("Error getting expiry claim from token: %w", err)
 	}
 	userID, err := inspector.GetUserIDFromToken(token)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting user ID from token: %v", err)
+		return nil, fmt.Errorf("Error getting user ID from token: %w", err)
 	}
 	hasPaidFeatures, err := inspector.GetHasPaidFeaturesFromToken(token)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting has paid features claim from token: %v", err)
+		return nil, fmt.Errorf("Error getting has paid features claim from token: %w", err)
 	}
-	return &TokenClaims{
+	claims := &TokenClaims{
 		Email:           *email,
 		Type:            *tokenType,
 		Expiry:          *expiry,
 		UserID:          *userID,
 		HasPaidFeatures: *hasPaidFeatures,
-	}, nil
+	}
+	if err := inspector.addOptionalClaims(token, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// addOptionalClaims adds the optional registered, refresh family and authorization claims present in the token
+func (inspector *TokenInspector) addOptionalClaims(token *jwt.Token, claims *TokenClaims) error {
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return fmt.Errorf("%w: claims are not a JSON object", ErrTokenMalformed)
+	}
+	if _, exists := mapClaims[IssuerClaim]; exists {
+		issuer, err := inspector.GetIssuerFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.Issuer = *issuer
+	}
+	if _, exists := mapClaims[AudienceClaim]; exists {
+		audience, err := inspector.GetAudienceFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.Audience = audience
+	}
+	if _, exists := mapClaims[SubjectClaim]; exists {
+		subject, err := inspector.GetSubjectFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.Subject = *subject
+	}
+	if _, exists := mapClaims[NotBeforeClaim]; exists {
+		notBefore, err := inspector.GetNotBeforeFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.NotBefore = *notBefore
+	}
+	if _, exists := mapClaims[IssuedAtClaim]; exists {
+		issuedAt, err := inspector.GetIssuedAtFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.IssuedAt = *issuedAt
+	}
+	if _, exists := mapClaims[JWTIDClaim]; exists {
+		jwtID, err := inspector.GetJWTIDFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.JWTID = *jwtID
+	}
+	if _, exists := mapClaims[FamilyIDClaim]; exists {
+		familyID, err := inspector.GetFamilyIDFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.FamilyID = *familyID
+	}
+	if _, exists := mapClaims[RolesClaim]; exists {
+		roles, err := inspector.GetRolesFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.Roles = roles
+	}
+	if _, exists := mapClaims[ScopesClaim]; exists {
+		scopes, err := inspector.GetScopesFromToken(token)
+		if err != nil {
+			return err
+		}
+		claims.Scopes = scopes
+	}
+	return nil
 }
 
 // GetClaimsFromTokenString gets the email from a JWT token ßstringå
@@ -141,7 +322,7 @@ func (inspector *TokenInspector) GetClaimsFromTokenString(tokenStr string) (*Tok
 	// Parse the token
 	token, _, err := new(jwt.Parser).ParseUnverified(tokenStr, jwt.MapClaims{})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
 	}
 
 	return inspector.GetClaimsFromToken(token)
@@ -0,0 +1,153 @@
+package jwt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+// Default token lifetimes
+const (
+	DefaultAuthTokenLifetime              = 15 * time.Minute
+	DefaultRefreshTokenLifetime           = 7 * 24 * time.Hour
+	DefaultEmailVerificationTokenLifetime = 24 * time.Hour
+	DefaultResetPasswordTokenLifetime     = time.Hour
+	DefaultServiceTokenLifetime           = 5 * time.Minute
+)
+
+// TokenLifetimePolicy is the lifetime of the tokens of each type
+type TokenLifetimePolicy map[token.Type]time.Duration
+
+// DefaultTokenLifetimePolicy returns the default lifetime of each token type
+func DefaultTokenLifetimePolicy() TokenLifetimePolicy {
+	return TokenLifetimePolicy{
+		token.AuthTokenType:              DefaultAuthTokenLifetime,
+		token.RefreshTokenType:           DefaultRefreshTokenLifetime,
+		token.EmailVerificationTokenType: DefaultEmailVerificationTokenLifetime,
+		token.ResetPasswordTokenType:     DefaultResetPasswordTokenLifetime,
+	}
+}
+
+// IssuedToken is a signed token with its claims
+type IssuedToken struct {
+	Token  string
+	Claims *TokenClaims
+}
+
+// TokenIssuerer issues the tokens of each type
+type TokenIssuerer interface {
+	IssueAuthToken(email, userID string, hasPaidFeatures bool, extraClaims ...ClaimPair) (*IssuedToken, error)
+	IssueRefreshToken(email, userID string, hasPaidFeatures bool, extraClaims ...ClaimPair) (*IssuedToken, error)
+	IssueEmailVerificationToken(email, userID string, extraClaims ...ClaimPair) (*IssuedToken, error)
+	IssueResetPasswordToken(email, userID string, extraClaims ...ClaimPair) (*IssuedToken, error)
+}
+
+// TokenIssuer issues tokens with the lifetimes of its policy
+type TokenIssuer struct {
+	signer         TokenSignerer
+	tokenInspector TokenInspectorer
+	policy         TokenLifetimePolicy
+}
+
+var _ TokenIssuerer = &TokenIssuer{}
+
+// TokenIssuerOption configures a TokenIssuer
+type TokenIssuerOption func(*TokenIssuer)
+
+// WithTokenLifetime sets the lifetime of the tokens of a type
+func WithTokenLifetime(tokenType token.Type, lifetime time.Duration) TokenIssuerOption {
+	return func(tokenIssuer *TokenIssuer) {
+		tokenIssuer.policy[tokenType] = lifetime
+	}
+}
+
+// WithTokenLifetimePolicy sets the lifetime of the tokens of the types in the policy
+func WithTokenLifetimePolicy(policy TokenLifetimePolicy) TokenIssuerOption {
+	return func(tokenIssuer *TokenIssuer) {
+		for tokenType, lifetime := range policy {
+			tokenIssuer.policy[tokenType] = lifetime
+		}
+	}
+}
+
+// NewTokenIssuer creates a new token issuer, lifetimes not set by the options are the defaults
+func NewTokenIssuer(signer TokenSignerer, options ...TokenIssuerOption) TokenIssuerer {
+	tokenIssuer := &TokenIssuer{
+		signer:         signer,
+		tokenInspector: &TokenInspector{},
+		policy:         DefaultTokenLifetimePolicy(),
+	}
+	for _, option := range options {
+		option(tokenIssuer)
+	}
+	return tokenIssuer
+}
+
+// IssueAuthToken issues an authentication token
+func (tokenIssuer *TokenIssuer) IssueAuthToken(
+	email, userID string,
+	hasPaidFeatures bool,
+	extraClaims ...ClaimPair,
+) (*IssuedToken, error) {
+	return tokenIssuer.issue(token.AuthTokenType, email, userID, hasPaidFeatures, extraClaims)
+}
+
+// IssueRefreshToken issues a refresh token
+func (tokenIssuer *TokenIssuer) IssueRefreshToken(
+	email, userID string,
+	hasPaidFeatures bool,
+	extraClaims ...ClaimPair,
+) (*IssuedToken, error) {
+	return tokenIssuer.issue(token.RefreshTokenType, email, userID, hasPaidFeatures, extraClaims)
+}
+
+// IssueEmailVerificationToken issues an email verification token
+func (tokenIssuer *TokenIssuer) IssueEmailVerificationToken(
+	email, userID string,
+	extraClaims ...ClaimPair,
+) (*IssuedToken, error) {
+	return tokenIssuer.issue(token.EmailVerificationTokenType, email, userID, false, extraClaims)
+}
+
+// IssueResetPasswordToken issues a reset password token
+func (tokenIssuer *TokenIssuer) IssueResetPasswordToken(
+	email, userID string,
+	extraClaims ...ClaimPair,
+) (*IssuedToken, error) {
+	return tokenIssuer.issue(token.ResetPasswordTokenType, email, userID, false, extraClaims)
+}
+
+func (tokenIssuer *TokenIssuer) issue(
+	tokenType token.Type,
+	email, userID string,
+	hasPaidFeatures bool,
+	extraClaims []ClaimPair,
+) (*IssuedToken, error) {
+	lifetime, exists := tokenIssuer.policy[tokenType]
+	if !exists || lifetime <= 0 {
+		return nil, fmt.Errorf("No lifetime configured for token type %s", tokenType)
+	}
+	// iat and exp are computed from the same time so the token lasts exactly its lifetime
+	now := time.Now()
+	claims := append([]ClaimPair{
+		{EmailClaim, email},
+		{IssuedAtClaim, now},
+		{ExpiryClaim, now.Add(lifetime)},
+		{TypeClaim, tokenType},
+		{UserIDClaim, userID},
+		{HasPaidFeaturesClaim, hasPaidFeatures},
+	}, extraClaims...)
+	tokenString, err := tokenIssuer.signer.SignToken(claims...)
+	if err != nil {
+		return nil, fmt.Errorf("Error signing %s: %v", tokenType, err)
+	}
+	tokenClaims, err := tokenIssuer.tokenInspector.GetClaimsFromTokenString(*tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting claims from %s: %v", tokenType, err)
+	}
+	return &IssuedToken{
+		Token:  *tokenString,
+		Claims: tokenClaims,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+func TestTokenIssuer(t *testing.T) {
+	keyManager, err := NewKeyManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewTokenSignerFromKeyManager(keyManager)
+	verifier := newVerifierFromKeyManager(t, keyManager)
+	inspector := &TokenInspector{}
+	email := "test@email.com"
+	userID := "test-user-id"
+
+	assertIssuedToken := func(t *testing.T, issued *IssuedToken, tokenType token.Type, lifetime time.Duration) {
+		jwtToken, err := verifier.Verify(issued.Token)
+		assert.NoError(t, err)
+		claims, err := inspector.GetClaimsFromToken(jwtToken)
+		assert.NoError(t, err)
+		assert.Equal(t, claims, issued.Claims)
+		assert.Equal(t, tokenType, issued.Claims.Type)
+		assert.Equal(t, email, issued.Claims.Email)
+		assert.Equal(t, userID, issued.Claims.UserID)
+		assert.Equal(t, lifetime, issued.Claims.Expiry.Sub(issued.Claims.IssuedAt))
+	}
+
+	t.Run("Default_Lifetimes", func(t *testing.T) {
+		tokenIssuer := NewTokenIssuer(signer)
+
+		issued, err := tokenIssuer.IssueAuthToken(email, userID, true)
+		assert.NoError(t, err)
+		assertIssuedToken(t, issued, token.AuthTokenType, DefaultAuthTokenLifetime)
+		assert.True(t, issued.Claims.HasPaidFeatures)
+
+		issued, err = tokenIssuer.IssueRefreshToken(email, userID, false)
+		assert.NoError(t, err)
+		assertIssuedToken(t, issued, token.RefreshTokenType, DefaultRefreshTokenLifetime)
+
+		issued, err = tokenIssuer.IssueEmailVerificationToken(email, userID)
+		assert.NoError(t, err)
+		assertIssuedToken(t, issued, token.EmailVerificationTokenType, DefaultEmailVerificationTokenLifetime)
+
+		issued, err = tokenIssuer.IssueResetPasswordToken(email, userID)
+		assert.NoError(t, err)
+		assertIssuedToken(t, issued, token.ResetPasswordTokenType, DefaultResetPasswordTokenLifetime)
+	})
+
+	t.Run("Configured_Lifetimes", func(t *testing.T) {
+		tokenIssuer := NewTokenIssuer(
+			signer,
+			WithTokenLifetimePolicy(TokenLifetimePolicy{token.RefreshTokenType: 30 * 24 * time.Hour}),
+			WithTokenLifetime(token.AuthTokenType, 5*time.Minute),
+		)
+
+		issued, err := tokenIssuer.IssueAuthToken(email, userID, false)
+		assert.NoError(t, err)
+		assertIssuedToken(t, issued, token.AuthTokenType, 5*time.Minute)
+
+		issued, err = tokenIssuer.IssueRefreshToken(email, userID, false)
+		assert.NoError(t, err)
+		assertIssuedToken(t, issued, token.RefreshTokenType, 30*24*time.Hour)
+
+		issued, err = tokenIssuer.IssueResetPasswordToken(email, userID)
+		assert.NoError(t, err)
+		assertIssuedToken(t, issued, token.ResetPasswordTokenType, DefaultResetPasswordTokenLifetime)
+	})
+
+	t.Run("Missing_Lifetime", func(t *testing.T) {
+		tokenIssuer := NewTokenIssuer(signer, WithTokenLifetime(token.AuthTokenType, 0))
+
+		_, err := tokenIssuer.IssueAuthToken(email, userID, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("Extra_Claims", func(t *testing.T) {
+		tokenIssuer := NewTokenIssuer(signer)
+
+		issued, err := tokenIssuer.IssueAuthToken(
+			email,
+			userID,
+			false,
+			ClaimPair{SubjectClaim, userID},
+			ClaimPair{RolesClaim, []string{"admin"}},
+			ClaimPair{ScopesClaim, []string{"user:read", "user:write"}},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, issued.Claims.Subject)
+		assert.Equal(t, []string{"admin"}, issued.Claims.Roles)
+		assert.Equal(t, []string{"user:read", "user:write"}, issued.Claims.Scopes)
+	})
+}
@@ -1,12 +1,14 @@
 package jwt
 
 import (
-	"crypto/rsa"
+	"crypto"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 
 	"github.com/quadev-ltd/qd-common/pkg/token"
 )
@@ -16,9 +18,13 @@ type TokenSignerer interface {
 	SignToken(claims ...ClaimPair) (*string, error)
 }
 
-// TokenSigner signs tokens
+// TokenSigner signs tokens with the key of its key provider
 type TokenSigner struct {
-	rsaPrivateKey *rsa.PrivateKey
+	keyProvider SigningKeyProvider
+	// algorithm overrides the algorithm of the signing key when set
+	algorithm Algorithm
+	issuer    string
+	audience  []string
 }
 
 var _ TokenSignerer = &TokenSigner{}
@@ -29,35 +35,114 @@ type ClaimPair struct {
 	Value interface{}
 }
 
-// NewTokenSigner creates a new JWT signer
-func NewTokenSigner(rsaPrivateKey *rsa.PrivateKey) TokenSignerer {
-	return &TokenSigner{
-		rsaPrivateKey,
+// TokenSignerOption configures a TokenSigner
+type TokenSignerOption func(*TokenSigner)
+
+// WithSigningAlgorithm sets the signing algorithm, by default the algorithm of the
+// signing key is used, i.e. RS256 for RSA keys, ES256 for P-256 keys and EdDSA for Ed25519 keys
+func WithSigningAlgorithm(algorithm Algorithm) TokenSignerOption {
+	return func(tokenSigner *TokenSigner) {
+		tokenSigner.algorithm = algorithm
+	}
+}
+
+// WithIssuer sets the issuer claim of the signed tokens
+func WithIssuer(issuer string) TokenSignerOption {
+	return func(tokenSigner *TokenSigner) {
+		tokenSigner.issuer = issuer
+	}
+}
+
+// WithAudience sets the default audience claim of the signed tokens,
+// an AudienceClaim passed to SignToken takes precedence
+func WithAudience(audience ...string) TokenSignerOption {
+	return func(tokenSigner *TokenSigner) {
+		tokenSigner.audience = audience
+	}
+}
+
+// isNilSigner checks the signer is nil, including nil pointers such as a nil *rsa.PrivateKey
+func isNilSigner(privateKey crypto.Signer) bool {
+	if privateKey == nil {
+		return true
+	}
+	value := reflect.ValueOf(privateKey)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+// NewTokenSigner creates a new JWT signer, a missing private key is reported when signing
+func NewTokenSigner(privateKey crypto.Signer, options ...TokenSignerOption) TokenSignerer {
+	signingKey := SigningKey{
+		Algorithm: DefaultAlgorithm,
+	}
+	if !isNilSigner(privateKey) {
+		// Unsupported keys are reported when signing
+		signingKey.KeyID, _ = KeyID(privateKey.Public())
+		signingKey.Algorithm, _ = AlgorithmForKey(privateKey.Public(), DefaultAlgorithm)
+		signingKey.PrivateKey = privateKey
 	}
+	return NewTokenSignerWithKeyProvider(signingKey, options...)
 }
 
-// SignToken signs a JWT token
+// NewTokenSignerWithKeyProvider creates a new JWT signer getting the signing key from the key provider on every signature
+func NewTokenSignerWithKeyProvider(keyProvider SigningKeyProvider, options ...TokenSignerOption) TokenSignerer {
+	tokenSigner := &TokenSigner{
+		keyProvider: keyProvider,
+	}
+	for _, option := range options {
+		option(tokenSigner)
+	}
+	return tokenSigner
+}
+
+// NewTokenSignerFromKeyManager creates a new JWT signer with the active key and algorithm of the key manager,
+// keys rotated or reloaded by the key manager are used straight away
+func NewTokenSignerFromKeyManager(keyManager KeyManagerer, options ...TokenSignerOption) TokenSignerer {
+	return NewTokenSignerWithKeyProvider(keyManager, options...)
+}
+
+// SignToken signs a JWT token, a unique JWT ID is generated unless a JWTIDClaim is given.
+// Claim values can be any JSON serialisable value, time.Time values are stored as Unix timestamps.
 func (tokenSigner *TokenSigner) SignToken(claims ...ClaimPair) (*string, error) {
+	signingKey := tokenSigner.keyProvider.GetSigningKey()
+	if isNilSigner(signingKey.PrivateKey) {
+		return nil, fmt.Errorf("No private key to sign the token")
+	}
+	algorithm := signingKey.Algorithm
+	if tokenSigner.algorithm != "" {
+		algorithm = tokenSigner.algorithm
+	}
+	if !algorithm.IsCompatibleWith(signingKey.PrivateKey.Public()) {
+		return nil, fmt.Errorf("Signing algorithm %s does not support key type %T", algorithm, signingKey.PrivateKey)
+	}
 	tokenClaims := jwt.MapClaims{
 		IssuedAtClaim: time.Now().Unix(),
+		JWTIDClaim:    uuid.New().String(),
+	}
+	if tokenSigner.issuer != "" {
+		tokenClaims[IssuerClaim] = tokenSigner.issuer
+	}
+	if len(tokenSigner.audience) > 0 {
+		tokenClaims[AudienceClaim] = tokenSigner.audience
 	}
 	for _, claim := range claims {
 		switch v := claim.Value.(type) {
 		case time.Time:
 			tokenClaims[claim.Key] = v.Unix()
-		case string:
-			tokenClaims[claim.Key] = string(v)
 		case token.Type:
 			tokenClaims[claim.Key] = string(v)
-		case bool:
-			tokenClaims[claim.Key] = bool(v)
 		default:
-			return nil, fmt.Errorf("invalid claim value type: %v", reflect.TypeOf(claim.Value))
+			if _, err := json.Marshal(v); err != nil {
+				return nil, fmt.Errorf("invalid claim value type %v for claim %s: %v", reflect.TypeOf(claim.Value), claim.Key, err)
+			}
+			tokenClaims[claim.Key] = v
 		}
-
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, tokenClaims)
-	tokenString, err := token.SignedString(tokenSigner.rsaPrivateKey)
+	token := jwt.NewWithClaims(algorithm.SigningMethod(), tokenClaims)
+	if signingKey.KeyID != "" {
+		token.Header[KeyIDHeader] = signingKey.KeyID
+	}
+	tokenString, err := token.SignedString(signingKey.PrivateKey)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,229 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/quadev-ltd/qd-common/pb/gen/go/pb_authentication"
+)
+
+// DefaultTokenRefreshWindow is how long before their expiry the cached tokens are refreshed
+const DefaultTokenRefreshWindow = 30 * time.Second
+
+// TokenSource provides the tokens attached to the outgoing gRPC calls
+type TokenSource interface {
+	// Token gets a valid token, refreshing it when it is about to expire
+	Token(ctx context.Context) (string, error)
+	// Invalidate discards the token after it was rejected, so the next call to Token gets a new one
+	Invalidate(token string)
+}
+
+// StaticTokenSource always provides the same token
+type StaticTokenSource struct {
+	token string
+}
+
+var _ TokenSource = &StaticTokenSource{}
+
+// NewStaticTokenSource creates a token source with a fixed token
+func NewStaticTokenSource(token string) *StaticTokenSource {
+	return &StaticTokenSource{
+		token: token,
+	}
+}
+
+// Token gets the token
+func (source *StaticTokenSource) Token(ctx context.Context) (string, error) {
+	return source.token, nil
+}
+
+// Invalidate does nothing as the token cannot be replaced
+func (source *StaticTokenSource) Invalidate(token string) {}
+
+// TokenSourceOption configures the token sources caching their tokens
+type TokenSourceOption func(*tokenCache)
+
+// WithRefreshWindow sets how long before its expiry a token is refreshed
+func WithRefreshWindow(refreshWindow time.Duration) TokenSourceOption {
+	return func(cache *tokenCache) {
+		cache.refreshWindow = refreshWindow
+	}
+}
+
+// tokenCache caches a token until it is about to expire, the expiry is read from the exp claim
+type tokenCache struct {
+	mutex          sync.Mutex
+	token          string
+	expiry         time.Time
+	refreshWindow  time.Duration
+	tokenInspector TokenInspectorer
+}
+
+func newTokenCache(options ...TokenSourceOption) *tokenCache {
+	cache := &tokenCache{
+		refreshWindow:  DefaultTokenRefreshWindow,
+		tokenInspector: &TokenInspector{},
+	}
+	for _, option := range options {
+		option(cache)
+	}
+	return cache
+}
+
+func (cache *tokenCache) getExpiry(tokenString string) (time.Time, error) {
+	jwtToken, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	expiry, err := cache.tokenInspector.GetExpiryFromToken(jwtToken)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return *expiry, nil
+}
+
+// set caches the token, the mutex must be held
+func (cache *tokenCache) set(tokenString string) error {
+	expiry, err := cache.getExpiry(tokenString)
+	if err != nil {
+		return err
+	}
+	cache.token = tokenString
+	cache.expiry = expiry
+	return nil
+}
+
+// get gets the cached token, fetching a new one when there is none or it is about to expire
+func (cache *tokenCache) get(ctx context.Context, fetch func(ctx context.Context) (string, error)) (string, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.token != "" && time.Now().Add(cache.refreshWindow).Before(cache.expiry) {
+		return cache.token, nil
+	}
+	tokenString, err := fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := cache.set(tokenString); err != nil {
+		return "", err
+	}
+	return cache.token, nil
+}
+
+func (cache *tokenCache) invalidate(tokenString string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.token == tokenString {
+		cache.token = ""
+	}
+}
+
+// SignedTokenSource signs its own short lived tokens, e.g. for service to service calls
+type SignedTokenSource struct {
+	cache       *tokenCache
+	tokenSigner TokenSignerer
+	lifetime    time.Duration
+	claims      []ClaimPair
+}
+
+var _ TokenSource = &SignedTokenSource{}
+
+// NewSignedTokenSource creates a token source signing tokens with the claims which expire after the lifetime
+func NewSignedTokenSource(
+	tokenSigner TokenSignerer,
+	lifetime time.Duration,
+	claims []ClaimPair,
+	options ...TokenSourceOption,
+) *SignedTokenSource {
+	return &SignedTokenSource{
+		cache:       newTokenCache(options...),
+		tokenSigner: tokenSigner,
+		lifetime:    lifetime,
+		claims:      claims,
+	}
+}
+
+func (source *SignedTokenSource) signToken(ctx context.Context) (string, error) {
+	claims := append([]ClaimPair{{ExpiryClaim, time.Now().Add(source.lifetime)}}, source.claims...)
+	tokenString, err := source.tokenSigner.SignToken(claims...)
+	if err != nil {
+		return "", fmt.Errorf("Failed to sign token: %v", err)
+	}
+	return *tokenString, nil
+}
+
+// Token gets the signed token, a new token is signed when the previous one is about to expire
+func (source *SignedTokenSource) Token(ctx context.Context) (string, error) {
+	return source.cache.get(ctx, source.signToken)
+}
+
+// Invalidate discards the token so a new one is signed
+func (source *SignedTokenSource) Invalidate(token string) {
+	source.cache.invalidate(token)
+}
+
+// RefreshingTokenSource provides the auth token of a user, refreshing it through the
+// AuthenticationService RefreshToken call when it is about to expire or is rejected
+type RefreshingTokenSource struct {
+	cache          *tokenCache
+	client         pb_authentication.AuthenticationServiceClient
+	refreshToken   string
+	onTokenRefresh func(authToken, refreshToken string)
+}
+
+var _ TokenSource = &RefreshingTokenSource{}
+
+// NewRefreshingTokenSource creates a token source starting with the auth and refresh tokens,
+// the auth token may be empty to refresh it on the first call. Refresh tokens are rotated,
+// onTokenRefresh, which may be nil, is called with the new tokens so they can be persisted.
+func NewRefreshingTokenSource(
+	client pb_authentication.AuthenticationServiceClient,
+	authToken, refreshToken string,
+	onTokenRefresh func(authToken, refreshToken string),
+	options ...TokenSourceOption,
+) (*RefreshingTokenSource, error) {
+	source := &RefreshingTokenSource{
+		cache:          newTokenCache(options...),
+		client:         client,
+		refreshToken:   refreshToken,
+		onTokenRefresh: onTokenRefresh,
+	}
+	if authToken != "" {
+		if err := source.cache.set(authToken); err != nil {
+			return nil, fmt.Errorf("Failed to read auth token: %w", err)
+		}
+	}
+	return source, nil
+}
+
+// refresh exchanges the refresh token, the cache mutex is held so a single refresh happens at a time
+func (source *RefreshingTokenSource) refresh(ctx context.Context) (string, error) {
+	response, err := source.client.RefreshToken(
+		AddAuthorizationMetadataToContext(ctx, source.refreshToken),
+		&pb_authentication.RefreshTokenRequest{},
+	)
+	if err != nil {
+		return "", fmt.Errorf("Failed to refresh token: %w", ErrorFromStatus(err))
+	}
+	if response.RefreshToken != "" {
+		source.refreshToken = response.RefreshToken
+	}
+	if source.onTokenRefresh != nil {
+		source.onTokenRefresh(response.AuthToken, source.refreshToken)
+	}
+	return response.AuthToken, nil
+}
+
+// Token gets the auth token, refreshing it when it is about to expire
+func (source *RefreshingTokenSource) Token(ctx context.Context) (string, error) {
+	return source.cache.get(ctx, source.refresh)
+}
+
+// Invalidate discards the auth token so it is refreshed on the next call
+func (source *RefreshingTokenSource) Invalidate(token string) {
+	source.cache.invalidate(token)
+}
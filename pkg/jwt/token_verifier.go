@@ -1,9 +1,10 @@
 package jwt
 
 import (
-	"crypto/rsa"
-	"crypto/x509"
+	"context"
+	"crypto"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"time"
 
@@ -17,55 +18,280 @@ type TokenVerifierer interface {
 
 // TokenVerifier is responsible for generating and verifying JWT tokens
 type TokenVerifier struct {
-	publicKey      *rsa.PublicKey
-	tokenInspector TokenInspectorer
+	keySource         PublicKeySourcer
+	tokenInspector    TokenInspectorer
+	expectedIssuers   []string
+	expectedAudiences []string
+	leeway            time.Duration
+	revoker           Revokerer
 }
 
 var _ TokenVerifierer = &TokenVerifier{}
 
-func loadPublicKeyFromString(publicKeyPEM string) (*rsa.PublicKey, error) {
-	block, _ := pem.Decode([]byte(publicKeyPEM))
-	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
-	if err != nil {
-		return nil, err
+// TokenVerifierOption configures a TokenVerifier
+type TokenVerifierOption func(*TokenVerifier)
+
+// WithExpectedIssuers only accepts tokens issued by one of the issuers
+func WithExpectedIssuers(issuers ...string) TokenVerifierOption {
+	return func(authenticator *TokenVerifier) {
+		authenticator.expectedIssuers = issuers
 	}
+}
 
-	return publicKey.(*rsa.PublicKey), nil
+// WithExpectedAudiences only accepts tokens addressed to at least one of the audiences
+func WithExpectedAudiences(audiences ...string) TokenVerifierOption {
+	return func(authenticator *TokenVerifier) {
+		authenticator.expectedAudiences = audiences
+	}
 }
 
-// NewTokenVerifier creates a new JWT authenticator
-func NewTokenVerifier(publicKeyString string) (TokenVerifierer, error) {
-	publicKey, err := loadPublicKeyFromString(publicKeyString)
+// WithLeeway sets the clock skew tolerated when checking the exp, nbf and iat claims
+func WithLeeway(leeway time.Duration) TokenVerifierOption {
+	return func(authenticator *TokenVerifier) {
+		authenticator.leeway = leeway
+	}
+}
+
+// loadPublicKeysFromString parses every PEM block of the string indexing the keys by key ID
+func loadPublicKeysFromString(publicKeyPEM string) (map[string]crypto.PublicKey, error) {
+	publicKeys := map[string]crypto.PublicKey{}
+	rest := []byte(publicKeyPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		publicKey, err := parsePublicKeyBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		keyID, err := KeyID(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys[keyID] = publicKey
+	}
+	if len(publicKeys) == 0 {
+		return nil, fmt.Errorf("No public key found in PEM string")
+	}
+	return publicKeys, nil
+}
+
+// WithRevoker rejects the tokens revoked through the revoker
+func WithRevoker(revoker Revokerer) TokenVerifierOption {
+	return func(authenticator *TokenVerifier) {
+		authenticator.revoker = revoker
+	}
+}
+
+// NewTokenVerifier creates a new JWT authenticator from one or more PEM encoded public keys
+func NewTokenVerifier(publicKeyString string, options ...TokenVerifierOption) (TokenVerifierer, error) {
+	publicKeys, err := loadPublicKeysFromString(publicKeyString)
 	if err != nil {
 		return nil, err
 	}
-	tokenInspector := &TokenInspector{}
-	return &TokenVerifier{
-		publicKey,
-		tokenInspector,
-	}, nil
+	return NewTokenVerifierFromKeys(publicKeys, options...), nil
 }
 
-// Verify verifies a JWT token
-func (authenticator *TokenVerifier) Verify(tokenString string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+// NewTokenVerifierFromKeys creates a new JWT authenticator from public keys indexed by key ID
+func NewTokenVerifierFromKeys(publicKeys map[string]crypto.PublicKey, options ...TokenVerifierOption) TokenVerifierer {
+	return NewTokenVerifierWithKeySource(NewStaticKeySource(publicKeys), options...)
+}
+
+// NewTokenVerifierFromKeyManager creates a new JWT authenticator with the verification keys of the key manager,
+// keys rotated or reloaded by the key manager are used straight away
+func NewTokenVerifierFromKeyManager(keyManager KeyManagerer, options ...TokenVerifierOption) TokenVerifierer {
+	return NewTokenVerifierWithKeySource(NewKeyManagerKeySource(keyManager), options...)
+}
+
+// NewTokenVerifierWithKeySource creates a new JWT authenticator looking up the keys in the key source
+func NewTokenVerifierWithKeySource(keySource PublicKeySourcer, options ...TokenVerifierOption) TokenVerifierer {
+	authenticator := &TokenVerifier{
+		keySource:      keySource,
+		tokenInspector: &TokenInspector{},
+	}
+	for _, option := range options {
+		option(authenticator)
+	}
+	return authenticator
+}
+
+func (authenticator *TokenVerifier) parse(tokenString string, publicKey crypto.PublicKey) (*jwt.Token, error) {
+	// Time based claims are validated afterwards applying the leeway
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	return parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		verificationKey := publicKey
+		if verificationKey == nil {
+			keyID, ok := token.Header[KeyIDHeader].(string)
+			if !ok {
+				return nil, fmt.Errorf("Token Verifier: JWT Token key ID is not valid")
+			}
+			keyedPublicKey, err := authenticator.keySource.GetPublicKey(keyID)
+			if err != nil {
+				return nil, fmt.Errorf("Token Verifier: %w", err)
+			}
+			verificationKey = keyedPublicKey
+		}
+		// The key type decides the algorithm family, preventing algorithm confusion
+		if !isSigningMethodCompatible(token.Method, verificationKey) {
 			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
 		}
-		return authenticator.publicKey, nil
+		return verificationKey, nil
 	})
+}
+
+// parseWithAnyKey verifies tokens signed before key IDs were stamped against every known key
+func (authenticator *TokenVerifier) parseWithAnyKey(tokenString string) (*jwt.Token, error) {
+	publicKeys, err := authenticator.keySource.GetPublicKeys()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Token Verifier: %v", err)
 	}
-	if !token.Valid {
-		return nil, fmt.Errorf("Token Verifier: JWT Token is not valid")
+	lastErr := fmt.Errorf("%w: No public key available", ErrSignatureInvalid)
+	for _, publicKey := range publicKeys {
+		token, err := authenticator.parse(tokenString, publicKey)
+		if err == nil {
+			return token, nil
+		}
+		lastErr = err
+		validationErr, ok := err.(*jwt.ValidationError)
+		if !ok || validationErr.Errors&jwt.ValidationErrorSignatureInvalid == 0 {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// verificationError converts the parser validation errors into ErrTokenMalformed
+// and ErrSignatureInvalid, unknown keys and unexpected algorithms are signature errors
+// while failures to fetch the keys are returned unchanged
+func verificationError(err error) error {
+	var validationErr *jwt.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+	if errors.Is(validationErr.Inner, ErrKeysUnavailable) {
+		return validationErr.Inner
+	}
+	if validationErr.Errors&jwt.ValidationErrorMalformed != 0 {
+		return fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	return fmt.Errorf("%w: %v", ErrSignatureInvalid, err)
+}
+
+// Verify verifies a JWT token, the errors can be matched with errors.Is and errors.As against
+// ErrTokenMalformed, ErrSignatureInvalid, ErrTokenExpired, MissingClaimError and the other
+// verification errors, and converted into gRPC status errors with ToStatusError
+func (authenticator *TokenVerifier) Verify(tokenString string) (*jwt.Token, error) {
+	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return nil, verificationError(err)
+	}
+	if _, hasKeyID := token.Header[KeyIDHeader]; hasKeyID {
+		token, err = authenticator.parse(tokenString, nil)
+	} else {
+		token, err = authenticator.parseWithAnyKey(tokenString)
 	}
-	expiry, err := authenticator.tokenInspector.GetExpiryFromToken(token)
 	if err != nil {
+		return nil, verificationError(err)
+	}
+	if !token.Valid {
+		return nil, ErrSignatureInvalid
+	}
+	if err := authenticator.validateClaims(token); err != nil {
 		return nil, err
 	}
-	if expiry.Before(time.Now()) {
-		return nil, fmt.Errorf("Token Verifier: JWT Token is expired")
+	if authenticator.revoker != nil {
+		if err := authenticator.checkRevocation(token); err != nil {
+			return nil, err
+		}
 	}
 	return token, nil
 }
+
+func (authenticator *TokenVerifier) checkRevocation(token *jwt.Token) error {
+	revoked, err := isTokenRevoked(context.Background(), authenticator.revoker, token)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// isTokenRevoked checks the revoker for the token JWT ID and for the user tokens issued before a revocation
+func isTokenRevoked(ctx context.Context, revoker Revokerer, token *jwt.Token) (bool, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return false, fmt.Errorf("%w: claims are not a JSON object", ErrTokenMalformed)
+	}
+	jwtID, _ := claims[JWTIDClaim].(string)
+	userID, _ := claims[UserIDClaim].(string)
+	var issuedAt time.Time
+	if issuedAtValue, ok := claims[IssuedAtClaim].(float64); ok {
+		issuedAt = time.Unix(int64(issuedAtValue), 0)
+	}
+	revoked, err := revoker.IsRevoked(ctx, jwtID, userID, issuedAt)
+	if err != nil {
+		return false, fmt.Errorf("Token Verifier: Failed to check token revocation: %v", err)
+	}
+	return revoked, nil
+}
+
+func (authenticator *TokenVerifier) validateClaims(token *jwt.Token) error {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return fmt.Errorf("%w: claims are not a JSON object", ErrTokenMalformed)
+	}
+	now := time.Now()
+	expiry, err := authenticator.tokenInspector.GetExpiryFromToken(token)
+	if err != nil {
+		return err
+	}
+	if expiry.Add(authenticator.leeway).Before(now) {
+		return ErrTokenExpired
+	}
+	if _, exists := claims[NotBeforeClaim]; exists {
+		notBefore, err := authenticator.tokenInspector.GetNotBeforeFromToken(token)
+		if err != nil {
+			return err
+		}
+		if now.Add(authenticator.leeway).Before(*notBefore) {
+			return ErrTokenNotValidYet
+		}
+	}
+	if _, exists := claims[IssuedAtClaim]; exists {
+		issuedAt, err := authenticator.tokenInspector.GetIssuedAtFromToken(token)
+		if err != nil {
+			return err
+		}
+		if now.Add(authenticator.leeway).Before(*issuedAt) {
+			return ErrTokenIssuedInFuture
+		}
+	}
+	if len(authenticator.expectedIssuers) > 0 {
+		issuer, err := authenticator.tokenInspector.GetIssuerFromToken(token)
+		if err != nil || !containsAny(authenticator.expectedIssuers, *issuer) {
+			return ErrInvalidIssuer
+		}
+	}
+	if len(authenticator.expectedAudiences) > 0 {
+		audience, err := authenticator.tokenInspector.GetAudienceFromToken(token)
+		if err != nil || !containsAny(authenticator.expectedAudiences, audience...) {
+			return ErrInvalidAudience
+		}
+	}
+	return nil
+}
+
+func containsAny(expected []string, values ...string) bool {
+	for _, value := range values {
+		for _, expectedValue := range expected {
+			if value == expectedValue {
+				return true
+			}
+		}
+	}
+	return false
+}
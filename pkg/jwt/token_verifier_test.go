@@ -0,0 +1,192 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/quadev-ltd/qd-common/pkg/token"
+)
+
+const (
+	testIssuer   = "authentication-service"
+	testAudience = "gateway-service"
+)
+
+func TestTokenVerifierRegisteredClaims(t *testing.T) {
+	keyManager, err := NewKeyManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := keyManager.GetPublicKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewTokenSignerFromKeyManager(keyManager, WithIssuer(testIssuer), WithAudience(testAudience))
+	inspector := &TokenInspector{}
+
+	sign := func(t *testing.T, claims ...ClaimPair) string {
+		claims = append([]ClaimPair{
+			{EmailClaim, "test@email.com"},
+			{ExpiryClaim, time.Now().Add(time.Hour)},
+			{TypeClaim, token.AuthTokenType},
+			{UserIDClaim, "test-user-id"},
+			{HasPaidFeaturesClaim, false},
+		}, claims...)
+		tokenString, err := signer.SignToken(claims...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *tokenString
+	}
+	newVerifier := func(t *testing.T, options ...TokenVerifierOption) TokenVerifierer {
+		verifier, err := NewTokenVerifier(publicKey, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return verifier
+	}
+
+	t.Run("Claims_Are_Exposed", func(t *testing.T) {
+		notBefore := time.Now().Add(-time.Minute)
+		tokenString := sign(t, ClaimPair{SubjectClaim, "test-user-id"}, ClaimPair{NotBeforeClaim, notBefore})
+		verifier := newVerifier(t, WithExpectedIssuers(testIssuer), WithExpectedAudiences(testAudience))
+
+		jwtToken, err := verifier.Verify(tokenString)
+		assert.NoError(t, err)
+		claims, err := inspector.GetClaimsFromToken(jwtToken)
+		assert.NoError(t, err)
+		assert.Equal(t, testIssuer, claims.Issuer)
+		assert.Equal(t, []string{testAudience}, claims.Audience)
+		assert.Equal(t, "test-user-id", claims.Subject)
+		assert.Equal(t, notBefore.Unix(), claims.NotBefore.Unix())
+		assert.NotEmpty(t, claims.JWTID)
+		assert.False(t, claims.IssuedAt.IsZero())
+	})
+
+	t.Run("JWT_IDs_Are_Unique", func(t *testing.T) {
+		first, err := inspector.GetClaimsFromTokenString(sign(t))
+		assert.NoError(t, err)
+		second, err := inspector.GetClaimsFromTokenString(sign(t))
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.JWTID, second.JWTID)
+	})
+
+	t.Run("Unexpected_Issuer", func(t *testing.T) {
+		_, err := newVerifier(t, WithExpectedIssuers("other-service")).Verify(sign(t))
+
+		assert.True(t, errors.Is(err, ErrInvalidIssuer))
+	})
+
+	t.Run("Unexpected_Audience", func(t *testing.T) {
+		_, err := newVerifier(t, WithExpectedAudiences("email-service")).Verify(sign(t))
+
+		assert.True(t, errors.Is(err, ErrInvalidAudience))
+	})
+
+	t.Run("Audience_Claim_Overrides_Default", func(t *testing.T) {
+		tokenString := sign(t, ClaimPair{AudienceClaim, []string{"email-service", "image-analysis-service"}})
+
+		_, err := newVerifier(t, WithExpectedAudiences("image-analysis-service")).Verify(tokenString)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not_Valid_Yet", func(t *testing.T) {
+		tokenString := sign(t, ClaimPair{NotBeforeClaim, time.Now().Add(time.Minute)})
+
+		_, err := newVerifier(t).Verify(tokenString)
+		assert.True(t, errors.Is(err, ErrTokenNotValidYet))
+
+		_, err = newVerifier(t, WithLeeway(2*time.Minute)).Verify(tokenString)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		tokenString := sign(t, ClaimPair{ExpiryClaim, time.Now().Add(-time.Minute)})
+
+		_, err := newVerifier(t).Verify(tokenString)
+		assert.True(t, errors.Is(err, ErrTokenExpired))
+
+		_, err = newVerifier(t, WithLeeway(2*time.Minute)).Verify(tokenString)
+		assert.NoError(t, err)
+	})
+}
+
+func TestTokenVerifierTypedErrors(t *testing.T) {
+	keyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	otherKeyManager, err := NewKeyManager("", WithKeyStore(NewMemoryKeyStore()))
+	assert.NoError(t, err)
+	verifier := NewTokenVerifierFromKeyManager(keyManager)
+	inspector := &TokenInspector{}
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := verifier.Verify("invalid.token.value")
+
+		assert.True(t, errors.Is(err, ErrTokenMalformed))
+	})
+
+	t.Run("Signature_Invalid", func(t *testing.T) {
+		tokenString := signTestToken(t, NewTokenSignerWithKeyProvider(SigningKey{
+			KeyID:      keyManager.GetKeyID(),
+			PrivateKey: otherKeyManager.GetPrivateKey(),
+			Algorithm:  RS256,
+		}))
+
+		_, err := verifier.Verify(tokenString)
+		assert.True(t, errors.Is(err, ErrSignatureInvalid))
+	})
+
+	t.Run("Unknown_Key", func(t *testing.T) {
+		_, err := verifier.Verify(signTestToken(t, NewTokenSignerFromKeyManager(otherKeyManager)))
+
+		assert.True(t, errors.Is(err, ErrSignatureInvalid))
+	})
+
+	t.Run("Keys_Unavailable", func(t *testing.T) {
+		unavailableVerifier := NewTokenVerifierWithKeySource(NewCachingKeySource(&failingKeyFetcher{}, time.Hour))
+
+		_, err := unavailableVerifier.Verify(signTestToken(t, NewTokenSignerFromKeyManager(keyManager)))
+
+		assert.True(t, errors.Is(err, ErrKeysUnavailable))
+		assert.False(t, errors.Is(err, ErrSignatureInvalid))
+		assert.Equal(t, codes.Unavailable, status.Code(ToStatusError(err)))
+	})
+
+	t.Run("Missing_Claim", func(t *testing.T) {
+		tokenString, err := NewTokenSignerFromKeyManager(keyManager).SignToken(ClaimPair{TypeClaim, token.AuthTokenType})
+		assert.NoError(t, err)
+
+		_, err = verifier.Verify(*tokenString)
+		var missingClaimErr *MissingClaimError
+		assert.True(t, errors.As(err, &missingClaimErr))
+		assert.Equal(t, ExpiryClaim, missingClaimErr.Name)
+		assert.True(t, errors.Is(err, ErrMissingClaim))
+	})
+
+	t.Run("Invalid_Claim", func(t *testing.T) {
+		jwtToken, err := verifier.Verify(signTestToken(t, NewTokenSignerFromKeyManager(keyManager)))
+		assert.NoError(t, err)
+		_, err = inspector.GetClaimsFromToken(jwtToken)
+		assert.True(t, errors.Is(err, ErrMissingClaim))
+
+		tokenString, err := NewTokenSignerFromKeyManager(keyManager).SignToken(
+			ClaimPair{ExpiryClaim, time.Now().Add(time.Hour)},
+			ClaimPair{EmailClaim, 42},
+		)
+		assert.NoError(t, err)
+		jwtToken, err = verifier.Verify(*tokenString)
+		assert.NoError(t, err)
+		_, err = inspector.GetEmailFromToken(jwtToken)
+		var invalidClaimErr *InvalidClaimError
+		assert.True(t, errors.As(err, &invalidClaimErr))
+		assert.Equal(t, EmailClaim, invalidClaimErr.Name)
+		assert.True(t, errors.Is(err, ErrTokenMalformed))
+	})
+}
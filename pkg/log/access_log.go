@@ -0,0 +1,360 @@
+package log
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Field keys of the access log entries
+const (
+	MethodFieldKey       = "method"
+	RouteFieldKey        = "route"
+	HTTPMethodFieldKey   = "http_method"
+	CodeFieldKey         = "code"
+	StatusFieldKey       = "status"
+	DurationFieldKey     = "duration"
+	PeerFieldKey         = "peer"
+	UserIDFieldKey       = "user_id"
+	RequestSizeFieldKey  = "request_size"
+	ResponseSizeFieldKey = "response_size"
+	SlowFieldKey         = "slow"
+)
+
+// AccessLogMessage is the message of the access log entries
+const AccessLogMessage = "Access log"
+
+// DefaultExcludedMethods are the health check methods excluded from the access logs
+var DefaultExcludedMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+type accessLogConfig struct {
+	slowCallThreshold time.Duration
+	excludedMethods   map[string]bool
+	userIDExtractor   func(ctx context.Context) string
+}
+
+// AccessLogOption configures the access log interceptors and middleware
+type AccessLogOption func(*accessLogConfig)
+
+// WithSlowCallThreshold logs the calls lasting longer than the threshold as warnings
+func WithSlowCallThreshold(threshold time.Duration) AccessLogOption {
+	return func(logConfig *accessLogConfig) {
+		logConfig.slowCallThreshold = threshold
+	}
+}
+
+// WithExcludedMethods excludes the gRPC full methods, or the Gin routes, from the access logs
+// on top of DefaultExcludedMethods
+func WithExcludedMethods(methods ...string) AccessLogOption {
+	return func(logConfig *accessLogConfig) {
+		for _, method := range methods {
+			logConfig.excludedMethods[method] = true
+		}
+	}
+}
+
+// WithUserIDExtractor gets the user ID of the Gin requests whose user ID was not set with
+// SetAccessLogUserID, from the request context once it is handled, which holds the values added
+// by the inner middleware. It does not apply to the gRPC interceptors, which do not see the
+// context their inner interceptors pass to the handler, the gRPC user ID is set with
+// SetAccessLogUserID
+func WithUserIDExtractor(userIDExtractor func(ctx context.Context) string) AccessLogOption {
+	return func(logConfig *accessLogConfig) {
+		logConfig.userIDExtractor = userIDExtractor
+	}
+}
+
+func newAccessLogConfig(options ...AccessLogOption) *accessLogConfig {
+	logConfig := &accessLogConfig{
+		excludedMethods: map[string]bool{},
+	}
+	for _, method := range DefaultExcludedMethods {
+		logConfig.excludedMethods[method] = true
+	}
+	for _, option := range options {
+		option(logConfig)
+	}
+	return logConfig
+}
+
+// accessLogEntry is the information logged about a call
+type accessLogEntry struct {
+	fields      []Field
+	duration    time.Duration
+	err         error
+	serverError bool
+}
+
+func (logConfig *accessLogConfig) log(logger Loggerer, entry accessLogEntry) {
+	fields := append(entry.fields, Duration(DurationFieldKey, entry.duration))
+	slow := logConfig.slowCallThreshold > 0 && entry.duration > logConfig.slowCallThreshold
+	if slow {
+		fields = append(fields, Bool(SlowFieldKey, true))
+	}
+	switch {
+	case entry.serverError:
+		logger.Error(entry.err, AccessLogMessage, fields...)
+	case slow:
+		logger.Warn(AccessLogMessage, fields...)
+	default:
+		logger.Info(AccessLogMessage, fields...)
+	}
+}
+
+// getLogger gets the logger of the context, added by the logger interceptor or middleware,
+// or a new logger when there is none
+func getLogger(ctx context.Context, logFactory Factoryer) Loggerer {
+	if logger, err := GetLoggerFromContext(ctx); err == nil {
+		return logger
+	}
+	return logFactory.NewLogger()
+}
+
+type accessLogDetailsKey string
+
+// AccessLogDetailsKey is the key of the details of the call set by the inner handlers in the context
+const AccessLogDetailsKey accessLogDetailsKey = "access_log_details"
+
+// accessLogDetails are the details of the call only known to the inner interceptors and handlers,
+// the access log adds them to the context so they can be set after the call is authenticated
+type accessLogDetails struct {
+	mutex  sync.Mutex
+	userID string
+}
+
+func withAccessLogDetails(ctx context.Context) (context.Context, *accessLogDetails) {
+	details := &accessLogDetails{}
+	return context.WithValue(ctx, AccessLogDetailsKey, details), details
+}
+
+// SetAccessLogUserID sets the user ID logged in the access log entry of the call, it is called by
+// the authentication interceptors and middleware running inside the access log. It does nothing
+// when the call is not access logged.
+func SetAccessLogUserID(ctx context.Context, userID string) {
+	if details, ok := ctx.Value(AccessLogDetailsKey).(*accessLogDetails); ok {
+		details.mutex.Lock()
+		defer details.mutex.Unlock()
+		details.userID = userID
+	}
+}
+
+func (details *accessLogDetails) getUserID() string {
+	details.mutex.Lock()
+	defer details.mutex.Unlock()
+	return details.userID
+}
+
+func getUserIDFields(userID string) []Field {
+	if userID == "" {
+		return nil
+	}
+	return []Field{String(UserIDFieldKey, userID)}
+}
+
+func getPeerFields(ctx context.Context) []Field {
+	if callPeer, ok := peer.FromContext(ctx); ok && callPeer.Addr != nil {
+		return []Field{String(PeerFieldKey, callPeer.Addr.String())}
+	}
+	return nil
+}
+
+func messageSize(message interface{}) int {
+	if protoMessage, ok := message.(proto.Message); ok {
+		return proto.Size(protoMessage)
+	}
+	return 0
+}
+
+// isServerErrorCode checks the code is an error of the server rather than of the call
+func isServerErrorCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return true
+	default:
+		return false
+	}
+}
+
+func grpcAccessLogFields(
+	ctx context.Context,
+	details *accessLogDetails,
+	fullMethod string,
+	err error,
+) ([]Field, bool) {
+	code := status.Code(err)
+	fields := []Field{
+		String(MethodFieldKey, fullMethod),
+		String(CodeFieldKey, code.String()),
+	}
+	fields = append(fields, getPeerFields(ctx)...)
+	fields = append(fields, getUserIDFields(details.getUserID())...)
+	return fields, isServerErrorCode(code)
+}
+
+// CreateAccessLogInterceptor is the interceptor that logs an entry per gRPC call with its method,
+// code, duration, peer, user ID and message sizes. It must be chained after CreateLoggerInterceptor
+// to log with the correlation ID, and before the authentication interceptor so the rejected calls
+// are logged too, the authentication interceptor sets the user ID with SetAccessLogUserID.
+func CreateAccessLogInterceptor(
+	logFactory Factoryer,
+	options ...AccessLogOption,
+) grpc.UnaryServerInterceptor {
+	logConfig := newAccessLogConfig(options...)
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if logConfig.excludedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		detailsCtx, details := withAccessLogDetails(ctx)
+		resp, err := handler(detailsCtx, req)
+		fields, serverError := grpcAccessLogFields(ctx, details, info.FullMethod, err)
+		fields = append(
+			fields,
+			Int(RequestSizeFieldKey, messageSize(req)),
+			Int(ResponseSizeFieldKey, messageSize(resp)),
+		)
+		logConfig.log(getLogger(ctx, logFactory), accessLogEntry{
+			fields:      fields,
+			duration:    time.Since(start),
+			err:         err,
+			serverError: serverError,
+		})
+		return resp, err
+	}
+}
+
+// accessLogServerStream is a server stream counting the sizes of its messages,
+// its context holds the access log details
+type accessLogServerStream struct {
+	grpc.ServerStream
+	ctx          context.Context
+	requestSize  int
+	responseSize int
+}
+
+// Context returns the context holding the access log details
+func (stream *accessLogServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+// RecvMsg receives a message and counts its size
+func (stream *accessLogServerStream) RecvMsg(message interface{}) error {
+	err := stream.ServerStream.RecvMsg(message)
+	if err == nil {
+		stream.requestSize += messageSize(message)
+	}
+	return err
+}
+
+// SendMsg sends a message and counts its size
+func (stream *accessLogServerStream) SendMsg(message interface{}) error {
+	err := stream.ServerStream.SendMsg(message)
+	if err == nil {
+		stream.responseSize += messageSize(message)
+	}
+	return err
+}
+
+// CreateAccessLogStreamInterceptor is the streaming version of CreateAccessLogInterceptor,
+// the sizes are the totals of the messages received and sent
+func CreateAccessLogStreamInterceptor(
+	logFactory Factoryer,
+	options ...AccessLogOption,
+) grpc.StreamServerInterceptor {
+	logConfig := newAccessLogConfig(options...)
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if logConfig.excludedMethods[info.FullMethod] {
+			return handler(srv, stream)
+		}
+		start := time.Now()
+		ctx := stream.Context()
+		detailsCtx, details := withAccessLogDetails(ctx)
+		accessLogStream := &accessLogServerStream{
+			ServerStream: stream,
+			ctx:          detailsCtx,
+		}
+		err := handler(srv, accessLogStream)
+		fields, serverError := grpcAccessLogFields(ctx, details, info.FullMethod, err)
+		fields = append(
+			fields,
+			Int(RequestSizeFieldKey, accessLogStream.requestSize),
+			Int(ResponseSizeFieldKey, accessLogStream.responseSize),
+		)
+		logConfig.log(getLogger(ctx, logFactory), accessLogEntry{
+			fields:      fields,
+			duration:    time.Since(start),
+			err:         err,
+			serverError: serverError,
+		})
+		return err
+	}
+}
+
+// CreateGinAccessLogMiddleware is the middleware that logs an entry per request with its route,
+// HTTP method and status, duration, client IP, user ID and body sizes. It must be used after
+// CreateGinLoggerMiddleware to log with the correlation ID, and before the authentication
+// middleware so the rejected requests are logged too.
+func CreateGinAccessLogMiddleware(
+	logFactory Factoryer,
+	options ...AccessLogOption,
+) gin.HandlerFunc {
+	logConfig := newAccessLogConfig(options...)
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		if logConfig.excludedMethods[route] {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		detailsCtx, details := withAccessLogDetails(c.Request.Context())
+		c.Request = c.Request.WithContext(detailsCtx)
+		c.Next()
+		fields := []Field{
+			String(RouteFieldKey, route),
+			String(HTTPMethodFieldKey, c.Request.Method),
+			Int(StatusFieldKey, c.Writer.Status()),
+			String(PeerFieldKey, c.ClientIP()),
+			Int64(RequestSizeFieldKey, max(c.Request.ContentLength, 0)),
+			Int(ResponseSizeFieldKey, max(c.Writer.Size(), 0)),
+		}
+		userID := details.getUserID()
+		if userID == "" && logConfig.userIDExtractor != nil {
+			userID = logConfig.userIDExtractor(c.Request.Context())
+		}
+		fields = append(fields, getUserIDFields(userID)...)
+		var err error
+		if lastError := c.Errors.Last(); lastError != nil {
+			err = lastError.Err
+		}
+		logConfig.log(getLogger(c.Request.Context(), logFactory), accessLogEntry{
+			fields:      fields,
+			duration:    time.Since(start),
+			err:         err,
+			serverError: c.Writer.Status() >= http.StatusInternalServerError,
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func readAccessLogEntries(t *testing.T, buffer *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestCreateAccessLogInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	peerContext := func() context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000},
+		})
+	}
+
+	t.Run("Logs_Call", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		interceptor := CreateAccessLogInterceptor(NewLogFactory("development", WithOutput(buffer)))
+		request := &grpc_health_v1.HealthCheckRequest{Service: "test"}
+
+		_, err := interceptor(peerContext(), request, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			SetAccessLogUserID(ctx, "1234")
+			return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+		})
+
+		assert.NoError(t, err)
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "info", entries[0]["level"])
+		assert.Equal(t, AccessLogMessage, entries[0]["message"])
+		assert.Equal(t, "/test.Service/Method", entries[0][MethodFieldKey])
+		assert.Equal(t, "OK", entries[0][CodeFieldKey])
+		assert.Equal(t, "10.0.0.1:5000", entries[0][PeerFieldKey])
+		assert.Equal(t, "1234", entries[0][UserIDFieldKey])
+		assert.Equal(t, float64(6), entries[0][RequestSizeFieldKey])
+		assert.Equal(t, float64(2), entries[0][ResponseSizeFieldKey])
+		assert.Contains(t, entries[0], DurationFieldKey)
+	})
+
+	t.Run("User_ID_Set_By_Inner_Interceptor", func(t *testing.T) {
+		type userKey string
+		buffer := &bytes.Buffer{}
+		interceptor := CreateAccessLogInterceptor(
+			NewLogFactory("development", WithOutput(buffer)),
+			WithUserIDExtractor(func(ctx context.Context) string {
+				userID, _ := ctx.Value(userKey("user")).(string)
+				return userID
+			}),
+		)
+		authenticationInterceptor := func(ctx context.Context, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx = context.WithValue(ctx, userKey("user"), "extracted-user-id")
+			SetAccessLogUserID(ctx, "authenticated-user-id")
+			return handler(ctx, req)
+		}
+
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return authenticationInterceptor(ctx, req, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			})
+		})
+
+		assert.NoError(t, err)
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "authenticated-user-id", entries[0][UserIDFieldKey])
+	})
+
+	t.Run("User_ID_Extractor_Not_Applied", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		interceptor := CreateAccessLogInterceptor(
+			NewLogFactory("development", WithOutput(buffer)),
+			WithUserIDExtractor(func(ctx context.Context) string {
+				return "extracted-user-id"
+			}),
+		)
+
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.NotContains(t, entries[0], UserIDFieldKey)
+	})
+
+	t.Run("Logs_Server_Error", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		interceptor := CreateAccessLogInterceptor(NewLogFactory("development", WithOutput(buffer)))
+
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.Internal, "internal error")
+		})
+
+		assert.Error(t, err)
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, "Internal", entries[0][CodeFieldKey])
+		assert.Contains(t, entries[0][ErrorFieldKey], "internal error")
+		assert.NotContains(t, entries[0], UserIDFieldKey)
+	})
+
+	t.Run("Logs_Slow_Call", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		interceptor := CreateAccessLogInterceptor(
+			NewLogFactory("development", WithOutput(buffer)),
+			WithSlowCallThreshold(time.Millisecond),
+		)
+
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			time.Sleep(5 * time.Millisecond)
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+
+		assert.Error(t, err)
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "NotFound", entries[0][CodeFieldKey])
+		assert.Equal(t, true, entries[0][SlowFieldKey])
+	})
+
+	t.Run("Uses_Context_Logger", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logFactory := NewLogFactory("development", WithOutput(buffer))
+		interceptor := CreateAccessLogInterceptor(logFactory)
+		ctx := AddCorrelationIDToIncomingContext(context.Background(), "test-correlation-id")
+		logger, err := logFactory.NewLoggerWithCorrelationID(ctx)
+		assert.NoError(t, err)
+		ctx = context.WithValue(ctx, LoggerKey, logger)
+
+		_, err = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "test-correlation-id", entries[0][CorrelationIDKey])
+	})
+
+	t.Run("Excluded_Methods", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		interceptor := CreateAccessLogInterceptor(
+			NewLogFactory("development", WithOutput(buffer)),
+			WithExcludedMethods("/test.Service/Method"),
+		)
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		}
+
+		_, err := interceptor(context.Background(), nil, info, handler)
+		assert.NoError(t, err)
+		_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+		assert.NoError(t, err)
+
+		assert.Empty(t, buffer.String())
+	})
+}
+
+type testAccessLogServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testAccessLogServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *testAccessLogServerStream) RecvMsg(message interface{}) error {
+	message.(*grpc_health_v1.HealthCheckRequest).Service = "test"
+	return nil
+}
+
+func (stream *testAccessLogServerStream) SendMsg(message interface{}) error {
+	return nil
+}
+
+func TestCreateAccessLogStreamInterceptor(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	interceptor := CreateAccessLogStreamInterceptor(NewLogFactory("development", WithOutput(buffer)))
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+	stream := &testAccessLogServerStream{ctx: context.Background()}
+
+	err := interceptor(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+		SetAccessLogUserID(stream.Context(), "1234")
+		request := &grpc_health_v1.HealthCheckRequest{}
+		assert.NoError(t, stream.RecvMsg(request))
+		for i := 0; i < 2; i++ {
+			response := &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}
+			assert.NoError(t, stream.SendMsg(response))
+		}
+		return errors.New("stream error")
+	})
+
+	assert.Error(t, err)
+	entries := readAccessLogEntries(t, buffer)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "error", entries[0]["level"])
+	assert.Equal(t, "/test.Service/Stream", entries[0][MethodFieldKey])
+	assert.Equal(t, "Unknown", entries[0][CodeFieldKey])
+	assert.Equal(t, float64(6), entries[0][RequestSizeFieldKey])
+	assert.Equal(t, float64(4), entries[0][ResponseSizeFieldKey])
+	assert.Equal(t, "1234", entries[0][UserIDFieldKey])
+}
+
+func TestCreateGinAccessLogMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(buffer *bytes.Buffer, options ...AccessLogOption) *gin.Engine {
+		router := gin.New()
+		router.Use(CreateGinAccessLogMiddleware(NewLogFactory("development", WithOutput(buffer)), options...))
+		router.POST("/users/:id", func(c *gin.Context) {
+			SetAccessLogUserID(c.Request.Context(), c.Param("id"))
+			c.String(http.StatusCreated, "created")
+		})
+		router.GET("/failure", func(c *gin.Context) {
+			c.AbortWithError(http.StatusInternalServerError, errors.New("failure"))
+		})
+		router.GET("/health", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	t.Run("Logs_Request", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		router := newRouter(buffer)
+
+		request := httptest.NewRequest(http.MethodPost, "/users/1234", strings.NewReader("body"))
+		request.RemoteAddr = "10.0.0.1:5000"
+		router.ServeHTTP(httptest.NewRecorder(), request)
+
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "info", entries[0]["level"])
+		assert.Equal(t, "/users/:id", entries[0][RouteFieldKey])
+		assert.Equal(t, http.MethodPost, entries[0][HTTPMethodFieldKey])
+		assert.Equal(t, float64(http.StatusCreated), entries[0][StatusFieldKey])
+		assert.Equal(t, "10.0.0.1", entries[0][PeerFieldKey])
+		assert.Equal(t, "1234", entries[0][UserIDFieldKey])
+		assert.Equal(t, float64(4), entries[0][RequestSizeFieldKey])
+		assert.Equal(t, float64(7), entries[0][ResponseSizeFieldKey])
+	})
+
+	t.Run("User_ID_Extractor", func(t *testing.T) {
+		type userKey string
+		buffer := &bytes.Buffer{}
+		router := gin.New()
+		router.Use(CreateGinAccessLogMiddleware(
+			NewLogFactory("development", WithOutput(buffer)),
+			WithUserIDExtractor(func(ctx context.Context) string {
+				userID, _ := ctx.Value(userKey("user")).(string)
+				return userID
+			}),
+		))
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), userKey("user"), "1234"))
+			c.Next()
+		})
+		router.GET("/users", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "1234", entries[0][UserIDFieldKey])
+	})
+
+	t.Run("Logs_Server_Error", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		router := newRouter(buffer)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/failure", nil))
+
+		entries := readAccessLogEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, float64(http.StatusInternalServerError), entries[0][StatusFieldKey])
+		assert.Equal(t, "failure", entries[0][ErrorFieldKey])
+	})
+
+	t.Run("Excluded_Routes", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		router := newRouter(buffer, WithExcludedMethods("/health"))
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Empty(t, buffer.String())
+	})
+}
@@ -1,14 +1,88 @@
 package log
 
 import (
+	"time"
+
 	"github.com/rs/zerolog"
 )
 
+// ErrorFieldKey is the key of the error field of the log entries
+const ErrorFieldKey = "error"
+
+// Field is a key/value pair added to a log entry so it can be queried
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String creates a string field
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int creates an int field
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 creates an int64 field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 creates a float64 field
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool creates a bool field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration creates a duration field, logged in milliseconds
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time creates a time field
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err creates the error field
+func Err(err error) Field {
+	return Field{Key: ErrorFieldKey, Value: err}
+}
+
+// Any creates a field of any value, it is logged as JSON when it has no dedicated type
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// toKeyValues converts the fields to the key/value pairs zerolog adds with their types
+func toKeyValues(fields []Field) []interface{} {
+	keyValues := make([]interface{}, 0, len(fields)*2)
+	for _, field := range fields {
+		keyValues = append(keyValues, field.Key, field.Value)
+	}
+	return keyValues
+}
+
 // Loggerer is the interface of the logger
 type Loggerer interface {
-	Error(err error, message string)
-	Info(message string)
-	Warn(message string)
+	Debug(message string, fields ...Field)
+	Info(message string, fields ...Field)
+	Warn(message string, fields ...Field)
+	Error(err error, message string, fields ...Field)
+	// Fatal logs the error and exits the process
+	Fatal(err error, message string, fields ...Field)
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(err error, format string, args ...interface{})
+	// With creates a child logger adding the fields to all its entries
+	With(fields ...Field) Loggerer
 }
 
 // Logger is the logger of the application
@@ -16,17 +90,63 @@ type Logger struct {
 	log zerolog.Logger
 }
 
-// Error logs an error
-func (logger *Logger) Error(err error, message string) {
-	logger.log.Error().Err(err).Msg(message)
+var _ Loggerer = &Logger{}
+
+func withFields(event *zerolog.Event, fields []Field) *zerolog.Event {
+	if len(fields) == 0 {
+		return event
+	}
+	return event.Fields(toKeyValues(fields))
+}
+
+// Debug logs a debug message
+func (logger *Logger) Debug(message string, fields ...Field) {
+	withFields(logger.log.Debug(), fields).Msg(message)
 }
 
 // Info logs an info
-func (logger *Logger) Info(message string) {
-	logger.log.Info().Msg(message)
+func (logger *Logger) Info(message string, fields ...Field) {
+	withFields(logger.log.Info(), fields).Msg(message)
 }
 
 // Warn logs a warning
-func (logger *Logger) Warn(message string) {
-	logger.log.Warn().Msg(message)
+func (logger *Logger) Warn(message string, fields ...Field) {
+	withFields(logger.log.Warn(), fields).Msg(message)
+}
+
+// Error logs an error
+func (logger *Logger) Error(err error, message string, fields ...Field) {
+	withFields(logger.log.Error().Err(err), fields).Msg(message)
+}
+
+// Fatal logs an error and exits the process with status 1
+func (logger *Logger) Fatal(err error, message string, fields ...Field) {
+	withFields(logger.log.Fatal().Err(err), fields).Msg(message)
+}
+
+// Debugf logs a formatted debug message
+func (logger *Logger) Debugf(format string, args ...interface{}) {
+	logger.log.Debug().Msgf(format, args...)
+}
+
+// Infof logs a formatted info
+func (logger *Logger) Infof(format string, args ...interface{}) {
+	logger.log.Info().Msgf(format, args...)
+}
+
+// Warnf logs a formatted warning
+func (logger *Logger) Warnf(format string, args ...interface{}) {
+	logger.log.Warn().Msgf(format, args...)
+}
+
+// Errorf logs an error with a formatted message
+func (logger *Logger) Errorf(err error, format string, args ...interface{}) {
+	logger.log.Error().Err(err).Msgf(format, args...)
+}
+
+// With creates a child logger adding the fields to all its entries
+func (logger *Logger) With(fields ...Field) Loggerer {
+	return &Logger{
+		log: logger.log.With().Fields(toKeyValues(fields)).Logger(),
+	}
 }
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"github.com/quadev-ltd/qd-common/pkg/config"
+)
+
+// LoggerConfigKey is the key of the logger configuration in the configuration files
+const LoggerConfigKey = "log"
+
+// Log formats
+const (
+	JSONFormat    = "json"
+	ConsoleFormat = "console"
+)
+
+// Log output types
+const (
+	StdoutOutput = "stdout"
+	StderrOutput = "stderr"
+	FileOutput   = "file"
+)
+
+// Defaults of the rotating file outputs
+const (
+	DefaultMaxFileSizeMB  = 100
+	DefaultMaxFileBackups = 5
+)
+
+// OutputConfig is the configuration of a log output
+type OutputConfig struct {
+	Type       string `mapstructure:"type"`
+	Path       string `mapstructure:"path"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups"`
+}
+
+// SamplingConfig is the configuration of the sampling of the info logs, the first Burst
+// entries of each Period are logged and then one in every Every entries, none when it is 0
+type SamplingConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Burst   uint32        `mapstructure:"burst"`
+	Period  time.Duration `mapstructure:"period"`
+	Every   uint32        `mapstructure:"every"`
+}
+
+// LoggerConfig is the configuration of the loggers created by the log factory
+type LoggerConfig struct {
+	Level         string            `mapstructure:"level"`
+	Format        string            `mapstructure:"format"`
+	Outputs       []OutputConfig    `mapstructure:"outputs"`
+	PackageLevels map[string]string `mapstructure:"package_levels"`
+	Sampling      SamplingConfig    `mapstructure:"sampling"`
+}
+
+// DefaultLoggerConfig is the configuration used when none is given, logging JSON to
+// stdout from warn level in production and from debug level in the other environments
+func DefaultLoggerConfig(environment string) LoggerConfig {
+	level := zerolog.DebugLevel
+	if environment == config.ProductionEnvironment {
+		level = zerolog.WarnLevel
+	}
+	return LoggerConfig{
+		Level:   level.String(),
+		Format:  JSONFormat,
+		Outputs: []OutputConfig{{Type: StdoutOutput}},
+	}
+}
+
+// LoadLoggerConfig loads the logger configuration under LoggerConfigKey of the viper instance
+// returned by config.SetupConfig, the missing values are taken from DefaultLoggerConfig
+func LoadLoggerConfig(v *viper.Viper, environment string) (*LoggerConfig, error) {
+	loggerConfig := DefaultLoggerConfig(environment)
+	if err := v.UnmarshalKey(LoggerConfigKey, &loggerConfig); err != nil {
+		return nil, fmt.Errorf("Error unmarshaling logger configuration: %v", err)
+	}
+	if err := loggerConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return &loggerConfig, nil
+}
+
+// Validate checks the levels, format and outputs of the configuration
+func (loggerConfig *LoggerConfig) Validate() error {
+	if _, err := zerolog.ParseLevel(loggerConfig.Level); err != nil {
+		return fmt.Errorf("Invalid log level %s: %v", loggerConfig.Level, err)
+	}
+	for packageName, level := range loggerConfig.PackageLevels {
+		if _, err := zerolog.ParseLevel(level); err != nil {
+			return fmt.Errorf("Invalid log level %s of package %s: %v", level, packageName, err)
+		}
+	}
+	if loggerConfig.Format != JSONFormat && loggerConfig.Format != ConsoleFormat {
+		return fmt.Errorf("Invalid log format %s", loggerConfig.Format)
+	}
+	for _, output := range loggerConfig.Outputs {
+		switch output.Type {
+		case StdoutOutput, StderrOutput:
+		case FileOutput:
+			if output.Path == "" {
+				return fmt.Errorf("Log file output without path")
+			}
+		default:
+			return fmt.Errorf("Invalid log output type %s", output.Type)
+		}
+	}
+	return nil
+}
+
+func (loggerConfig *LoggerConfig) getSampler() zerolog.Sampler {
+	if !loggerConfig.Sampling.Enabled {
+		return nil
+	}
+	sampler := &zerolog.BurstSampler{
+		Burst:  loggerConfig.Sampling.Burst,
+		Period: loggerConfig.Sampling.Period,
+	}
+	if loggerConfig.Sampling.Every > 0 {
+		sampler.NextSampler = &zerolog.BasicSampler{N: loggerConfig.Sampling.Every}
+	}
+	return zerolog.LevelSampler{InfoSampler: sampler}
+}
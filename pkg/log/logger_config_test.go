@@ -0,0 +1,108 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/config"
+)
+
+func newTestViper(t *testing.T, content string) *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("yml")
+	err := v.ReadConfig(strings.NewReader(content))
+	assert.NoError(t, err)
+	return v
+}
+
+func TestLoadLoggerConfig(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		v := newTestViper(t, "app_name: test")
+
+		loggerConfig, err := LoadLoggerConfig(v, config.ProductionEnvironment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultLoggerConfig(config.ProductionEnvironment), *loggerConfig)
+		assert.Equal(t, "warn", loggerConfig.Level)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		v := newTestViper(t, `
+log:
+  level: info
+  format: console
+  outputs:
+    - type: stderr
+    - type: file
+      path: /var/log/app.log
+      max_size_mb: 10
+      max_backups: 2
+  package_levels:
+    jwt: error
+  sampling:
+    enabled: true
+    burst: 5
+    period: 1s
+    every: 10
+`)
+
+		loggerConfig, err := LoadLoggerConfig(v, config.LocalEnvironment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, LoggerConfig{
+			Level:  "info",
+			Format: ConsoleFormat,
+			Outputs: []OutputConfig{
+				{Type: StderrOutput},
+				{Type: FileOutput, Path: "/var/log/app.log", MaxSizeMB: 10, MaxBackups: 2},
+			},
+			PackageLevels: map[string]string{"jwt": "error"},
+			Sampling: SamplingConfig{
+				Enabled: true,
+				Burst:   5,
+				Period:  time.Second,
+				Every:   10,
+			},
+		}, *loggerConfig)
+	})
+
+	t.Run("Invalid_Level", func(t *testing.T) {
+		v := newTestViper(t, "log:\n  level: loud")
+
+		_, err := LoadLoggerConfig(v, config.LocalEnvironment)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Invalid log level loud")
+	})
+
+	t.Run("Invalid_Package_Level", func(t *testing.T) {
+		v := newTestViper(t, "log:\n  package_levels:\n    jwt: loud")
+
+		_, err := LoadLoggerConfig(v, config.LocalEnvironment)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Invalid log level loud of package jwt")
+	})
+
+	t.Run("Invalid_Format", func(t *testing.T) {
+		v := newTestViper(t, "log:\n  format: xml")
+
+		_, err := LoadLoggerConfig(v, config.LocalEnvironment)
+
+		assert.Error(t, err)
+		assert.Equal(t, "Invalid log format xml", err.Error())
+	})
+
+	t.Run("File_Output_Without_Path", func(t *testing.T) {
+		v := newTestViper(t, "log:\n  outputs:\n    - type: file")
+
+		_, err := LoadLoggerConfig(v, config.LocalEnvironment)
+
+		assert.Error(t, err)
+		assert.Equal(t, "Log file output without path", err.Error())
+	})
+}
@@ -2,47 +2,150 @@ package log
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 
 	"github.com/rs/zerolog"
-
-	"github.com/quadev-ltd/qd-common/pkg/config"
 )
 
 // CorrelationIDKey is the key of the correlation ID in the metadata
 const CorrelationIDKey = "correlation_id"
 
+// PackageFieldKey is the key of the package field of the package loggers
+const PackageFieldKey = "package"
+
 // Factoryer is the interface for creating a log factory to create a logger
 type Factoryer interface {
 	NewLogger() Loggerer
+	NewPackageLogger(packageName string) Loggerer
 	NewLoggerWithCorrelationID(ctx context.Context) (Loggerer, error)
+	Close() error
 }
 
 // Factory is the factory for creating a logger
 type Factory struct {
-	environment string
+	environment  string
+	loggerConfig LoggerConfig
+	output       io.Writer
+	closers      []io.Closer
+	log          zerolog.Logger
+}
+
+var _ Factoryer = &Factory{}
+
+// FactoryOption configures a Factory
+type FactoryOption func(*Factory)
+
+// WithLoggerConfig sets the configuration of the loggers, e.g. loaded with LoadLoggerConfig,
+// an invalid configuration is replaced by DefaultLoggerConfig logging the validation error as a warning
+func WithLoggerConfig(loggerConfig LoggerConfig) FactoryOption {
+	return func(factory *Factory) {
+		factory.loggerConfig = loggerConfig
+	}
+}
+
+// WithOutput writes the logs to the writer instead of the outputs of the configuration
+func WithOutput(output io.Writer) FactoryOption {
+	return func(factory *Factory) {
+		factory.output = output
+	}
 }
 
 // NewLogFactory creates a new log factory
-func NewLogFactory(environment string) Factoryer {
-	return &Factory{
-		environment: environment,
+func NewLogFactory(environment string, options ...FactoryOption) Factoryer {
+	factory := &Factory{
+		environment:  environment,
+		loggerConfig: DefaultLoggerConfig(environment),
+	}
+	for _, option := range options {
+		option(factory)
+	}
+	configErr := factory.loggerConfig.Validate()
+	if configErr != nil {
+		factory.loggerConfig = DefaultLoggerConfig(environment)
 	}
+	factory.log = factory.newBaseLog()
+	if configErr != nil {
+		factory.NewLogger().Warn("Invalid logger configuration, using the default configuration", Err(configErr))
+	}
+	return factory
 }
 
-func setUpLog(log zerolog.Logger, environment string) zerolog.Logger {
-	if environment == config.ProductionEnvironment {
-		log = log.Level(zerolog.WarnLevel)
-	} else {
-		log = log.Level(zerolog.DebugLevel)
+func (logFactory *Factory) getOutputs() []io.Writer {
+	if logFactory.output != nil {
+		return []io.Writer{logFactory.output}
+	}
+	outputs := make([]io.Writer, 0, len(logFactory.loggerConfig.Outputs))
+	for _, outputConfig := range logFactory.loggerConfig.Outputs {
+		switch outputConfig.Type {
+		case StdoutOutput:
+			outputs = append(outputs, os.Stdout)
+		case StderrOutput:
+			outputs = append(outputs, os.Stderr)
+		case FileOutput:
+			maxSizeMB := outputConfig.MaxSizeMB
+			if maxSizeMB <= 0 {
+				maxSizeMB = DefaultMaxFileSizeMB
+			}
+			maxBackups := outputConfig.MaxBackups
+			if maxBackups <= 0 {
+				maxBackups = DefaultMaxFileBackups
+			}
+			fileWriter := NewRotatingFileWriter(outputConfig.Path, int64(maxSizeMB)*1024*1024, maxBackups)
+			logFactory.closers = append(logFactory.closers, fileWriter)
+			outputs = append(outputs, fileWriter)
+		}
+	}
+	return outputs
+}
+
+func (logFactory *Factory) newBaseLog() zerolog.Logger {
+	outputs := logFactory.getOutputs()
+	if logFactory.loggerConfig.Format == ConsoleFormat {
+		for index, output := range outputs {
+			outputs[index] = zerolog.ConsoleWriter{Out: output, NoColor: output != os.Stdout && output != os.Stderr}
+		}
+	}
+	var output io.Writer
+	switch len(outputs) {
+	case 0:
+		output = io.Discard
+	case 1:
+		output = outputs[0]
+	default:
+		output = zerolog.MultiLevelWriter(outputs...)
+	}
+	log := zerolog.New(output).With().Timestamp().Logger()
+	log = setUpLog(log, logFactory.loggerConfig.Level)
+	if sampler := logFactory.loggerConfig.getSampler(); sampler != nil {
+		log = log.Sample(sampler)
 	}
 	return log
 }
 
+func setUpLog(log zerolog.Logger, level string) zerolog.Logger {
+	parsedLevel, err := zerolog.ParseLevel(level)
+	if err != nil {
+		parsedLevel = zerolog.DebugLevel
+	}
+	return log.Level(parsedLevel)
+}
+
 // NewLogger creates a new logger for the given environment
 func (logFactory *Factory) NewLogger() Loggerer {
-	var log zerolog.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
-	log = setUpLog(log, logFactory.environment)
+	return &Logger{
+		log: logFactory.log,
+	}
+}
+
+// NewPackageLogger creates a new logger with the package field, its level is the level
+// of the package in the PackageLevels of the configuration when it is overridden
+func (logFactory *Factory) NewPackageLogger(packageName string) Loggerer {
+	log := logFactory.log.With().Str(PackageFieldKey, packageName).Logger()
+	if level, ok := logFactory.loggerConfig.PackageLevels[packageName]; ok {
+		log = setUpLog(log, level)
+	}
 	return &Logger{
 		log: log,
 	}
@@ -50,16 +153,20 @@ func (logFactory *Factory) NewLogger() Loggerer {
 
 // NewLoggerWithCorrelationID creates a new logger with the correlation ID
 func (logFactory *Factory) NewLoggerWithCorrelationID(ctx context.Context) (Loggerer, error) {
-	var log zerolog.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
 	correlationID, error := GetCorrelationIDFromContext(ctx)
 	if error != nil {
 		return nil, error
 	}
-	log = setUpLog(
-		log,
-		logFactory.environment,
-	).With().Str(CorrelationIDKey, *correlationID).Logger()
 	return &Logger{
-		log: log,
+		log: logFactory.log.With().Str(CorrelationIDKey, *correlationID).Logger(),
 	}, nil
 }
+
+// Close closes the log files of the file outputs
+func (logFactory *Factory) Close() error {
+	var errs []error
+	for _, closer := range logFactory.closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
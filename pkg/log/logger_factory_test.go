@@ -1,8 +1,12 @@
 package log
 
 import (
+	"bytes"
 	"context"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -51,3 +55,88 @@ func TestNewLoggerWithCorrelationID(t *testing.T) {
 		assert.Equal(t, err.Error(), "Metadata not found in context")
 	})
 }
+
+func TestNewLogFactoryWithOptions(t *testing.T) {
+	t.Run("Configured_Level", func(t *testing.T) {
+		loggerConfig := DefaultLoggerConfig("development")
+		loggerConfig.Level = "error"
+
+		factory := NewLogFactory("development", WithLoggerConfig(loggerConfig))
+
+		logger := factory.NewLogger().(*Logger)
+		assert.Equal(t, zerolog.ErrorLevel, logger.log.GetLevel())
+	})
+
+	t.Run("Invalid_Config_Uses_Default", func(t *testing.T) {
+		loggerConfig := DefaultLoggerConfig(config.ProductionEnvironment)
+		loggerConfig.Level = "loud"
+		buffer := &bytes.Buffer{}
+
+		factory := NewLogFactory(config.ProductionEnvironment, WithLoggerConfig(loggerConfig), WithOutput(buffer))
+
+		logger := factory.NewLogger().(*Logger)
+		assert.Equal(t, zerolog.WarnLevel, logger.log.GetLevel())
+		assert.Contains(t, buffer.String(), "Invalid logger configuration")
+		assert.Contains(t, buffer.String(), "Invalid log level loud")
+	})
+
+	t.Run("Console_Format", func(t *testing.T) {
+		loggerConfig := DefaultLoggerConfig("development")
+		loggerConfig.Format = ConsoleFormat
+		buffer := &bytes.Buffer{}
+
+		factory := NewLogFactory("development", WithLoggerConfig(loggerConfig), WithOutput(buffer))
+		factory.NewLogger().Info("console message", String("key", "value"))
+
+		assert.Contains(t, buffer.String(), "INF")
+		assert.Contains(t, buffer.String(), "console message")
+		assert.Contains(t, buffer.String(), "key=value")
+	})
+
+	t.Run("Package_Level", func(t *testing.T) {
+		loggerConfig := DefaultLoggerConfig("development")
+		loggerConfig.PackageLevels = map[string]string{"jwt": "error"}
+		buffer := &bytes.Buffer{}
+
+		factory := NewLogFactory("development", WithLoggerConfig(loggerConfig), WithOutput(buffer))
+		factory.NewPackageLogger("jwt").Info("filtered message")
+		factory.NewPackageLogger("email").Info("package message")
+
+		assert.NotContains(t, buffer.String(), "filtered message")
+		assert.Contains(t, buffer.String(), `"package":"email"`)
+		assert.Contains(t, buffer.String(), "package message")
+	})
+
+	t.Run("Info_Sampling", func(t *testing.T) {
+		loggerConfig := DefaultLoggerConfig("development")
+		loggerConfig.Sampling = SamplingConfig{
+			Enabled: true,
+			Burst:   2,
+			Period:  time.Minute,
+		}
+		buffer := &bytes.Buffer{}
+
+		factory := NewLogFactory("development", WithLoggerConfig(loggerConfig), WithOutput(buffer))
+		logger := factory.NewLogger()
+		for i := 0; i < 5; i++ {
+			logger.Info("info message")
+		}
+		logger.Warn("warn message")
+
+		assert.Equal(t, 2, strings.Count(buffer.String(), "info message"))
+		assert.Contains(t, buffer.String(), "warn message")
+	})
+
+	t.Run("File_Output", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		loggerConfig := DefaultLoggerConfig("development")
+		loggerConfig.Outputs = []OutputConfig{{Type: FileOutput, Path: path}}
+
+		factory := NewLogFactory("development", WithLoggerConfig(loggerConfig))
+		factory.NewLogger().Info("file message")
+		err := factory.Close()
+
+		assert.NoError(t, err)
+		assert.FileExists(t, path)
+	})
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(level zerolog.Level) (*Logger, *bytes.Buffer) {
+	buffer := &bytes.Buffer{}
+	return &Logger{
+		log: zerolog.New(buffer).Level(level),
+	}, buffer
+}
+
+func readEntry(t *testing.T, buffer *bytes.Buffer) map[string]interface{} {
+	var entry map[string]interface{}
+	err := json.Unmarshal(buffer.Bytes(), &entry)
+	assert.NoError(t, err)
+	buffer.Reset()
+	return entry
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("Levels", func(t *testing.T) {
+		logger, buffer := newTestLogger(zerolog.DebugLevel)
+
+		logger.Debug("debug message")
+		assert.Equal(t, "debug", readEntry(t, buffer)["level"])
+		logger.Info("info message")
+		assert.Equal(t, "info", readEntry(t, buffer)["level"])
+		logger.Warn("warn message")
+		assert.Equal(t, "warn", readEntry(t, buffer)["level"])
+		logger.Error(errors.New("test error"), "error message")
+		entry := readEntry(t, buffer)
+		assert.Equal(t, "error", entry["level"])
+		assert.Equal(t, "test error", entry[ErrorFieldKey])
+		assert.Equal(t, "error message", entry["message"])
+	})
+
+	t.Run("Level_Filtered", func(t *testing.T) {
+		logger, buffer := newTestLogger(zerolog.WarnLevel)
+
+		logger.Debug("debug message")
+		logger.Infof("info %s", "message")
+
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		logger, buffer := newTestLogger(zerolog.DebugLevel)
+
+		logger.Info(
+			"info message",
+			String("string", "value"),
+			Int("int", 1),
+			Bool("bool", true),
+			Duration("duration", 2*time.Second),
+			Err(errors.New("test error")),
+		)
+
+		entry := readEntry(t, buffer)
+		assert.Equal(t, "value", entry["string"])
+		assert.Equal(t, float64(1), entry["int"])
+		assert.Equal(t, true, entry["bool"])
+		assert.Equal(t, float64(2000), entry["duration"])
+		assert.Equal(t, "test error", entry[ErrorFieldKey])
+	})
+
+	t.Run("Formatted", func(t *testing.T) {
+		logger, buffer := newTestLogger(zerolog.DebugLevel)
+
+		logger.Errorf(errors.New("test error"), "failed %d times", 3)
+
+		entry := readEntry(t, buffer)
+		assert.Equal(t, "failed 3 times", entry["message"])
+		assert.Equal(t, "test error", entry[ErrorFieldKey])
+	})
+
+	t.Run("With", func(t *testing.T) {
+		logger, buffer := newTestLogger(zerolog.DebugLevel)
+
+		childLogger := logger.With(String("user_id", "1234"))
+		childLogger.Warn("warn message", Int("attempt", 2))
+
+		entry := readEntry(t, buffer)
+		assert.Equal(t, "1234", entry["user_id"])
+		assert.Equal(t, float64(2), entry["attempt"])
+
+		logger.Info("info message")
+		assert.NotContains(t, readEntry(t, buffer), "user_id")
+	})
+}
@@ -41,8 +41,7 @@ func CreateGinLoggerMiddleware(logFactory Factoryer) gin.HandlerFunc {
 
 // AddNewCorrelationIDToContext can ber used as a middleware that adds a new correlation ID to the context
 func AddNewCorrelationIDToContext(context *gin.Context) {
-	// Generate a random UUID
-	correlationID := uuid.New().String()
+	correlationID := GenerateCorrelationID()
 
 	ctxWithCorrelationID := AddCorrelationIDToIncomingContext(context.Request.Context(), correlationID)
 	context.Request = context.Request.WithContext(ctxWithCorrelationID)
@@ -58,8 +57,32 @@ func AddNewCorrelationIDToContext(context *gin.Context) {
 	context.Next()
 }
 
-// CreateLoggerInterceptor is the interceptor that intercepts the gRPC
-// calls and adds a logger with a correlation ID to the context
+// GenerateCorrelationID generates a new random correlation ID
+func GenerateCorrelationID() string {
+	return uuid.New().String()
+}
+
+// withCorrelationIDLogger adds the correlation ID of the incoming metadata, generating one when it is
+// missing, to the incoming and outgoing metadata, and a logger with the correlation ID to the context
+func withCorrelationIDLogger(ctx context.Context, logFactory Factoryer) (context.Context, string, error) {
+	var correlationID string
+	if existingCorrelationID, err := GetCorrelationIDFromContext(ctx); err == nil {
+		correlationID = *existingCorrelationID
+	} else {
+		correlationID = GenerateCorrelationID()
+		ctx = AddCorrelationIDToIncomingContext(ctx, correlationID)
+	}
+	ctx = AddCorrelationIDToOutgoingContext(ctx, correlationID)
+	logger, err := logFactory.NewLoggerWithCorrelationID(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return context.WithValue(ctx, LoggerKey, logger), correlationID, nil
+}
+
+// CreateLoggerInterceptor is the interceptor that intercepts the gRPC calls and adds a logger
+// with a correlation ID to the context. The correlation ID is generated when the call has none,
+// it is echoed back in the response header and propagated to the outgoing calls of the handler.
 func CreateLoggerInterceptor(
 	logFactory Factoryer,
 ) grpc.UnaryServerInterceptor {
@@ -69,15 +92,95 @@ func CreateLoggerInterceptor(
 		_ *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		logger, err := logFactory.NewLoggerWithCorrelationID(ctx)
+		newCtx, correlationID, err := withCorrelationIDLogger(ctx, logFactory)
 		if err != nil {
 			return nil, err
 		}
-		newCtx := context.WithValue(ctx, LoggerKey, logger)
+		if err := grpc.SetHeader(newCtx, metadata.Pairs(CorrelationIDKey, correlationID)); err != nil {
+			logger, _ := GetLoggerFromContext(newCtx)
+			logger.Debug("Failed to set correlation ID response header", Err(err))
+		}
 		return handler(newCtx, req)
 	}
 }
 
+// loggerServerStream is a server stream whose context has the logger with the correlation ID
+type loggerServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context with the logger
+func (stream *loggerServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+// CreateLoggerStreamInterceptor is the streaming version of CreateLoggerInterceptor
+func CreateLoggerStreamInterceptor(
+	logFactory Factoryer,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		_ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		newCtx, correlationID, err := withCorrelationIDLogger(stream.Context(), logFactory)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetHeader(metadata.Pairs(CorrelationIDKey, correlationID)); err != nil {
+			logger, _ := GetLoggerFromContext(newCtx)
+			logger.Debug("Failed to set correlation ID response header", Err(err))
+		}
+		return handler(srv, &loggerServerStream{
+			ServerStream: stream,
+			ctx:          newCtx,
+		})
+	}
+}
+
+// withOutgoingCorrelationID adds the correlation ID of the incoming context to the outgoing metadata,
+// a new correlation ID is generated when there is none and the outgoing metadata has no correlation ID
+func withOutgoingCorrelationID(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(CorrelationIDKey)) > 0 {
+		return ctx
+	}
+	if correlationID, err := GetCorrelationIDFromContext(ctx); err == nil {
+		return AddCorrelationIDToOutgoingContext(ctx, *correlationID)
+	}
+	return AddCorrelationIDToOutgoingContext(ctx, GenerateCorrelationID())
+}
+
+// CreateCorrelationIDClientInterceptor is the client interceptor that forwards the correlation ID
+// of the incoming call to the outgoing calls, instead of calling TransferCorrelationIDToOutgoingContext
+func CreateCorrelationIDClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		connection *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return invoker(withOutgoingCorrelationID(ctx), method, req, reply, connection, opts...)
+	}
+}
+
+// CreateCorrelationIDClientStreamInterceptor is the streaming version of CreateCorrelationIDClientInterceptor
+func CreateCorrelationIDClientStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		connection *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(withOutgoingCorrelationID(ctx), desc, connection, method, opts...)
+	}
+}
+
 // GetLoggerFromContext returns the logger from the context
 func GetLoggerFromContext(ctx context.Context) (Loggerer, error) {
 	if logger, ok := ctx.Value(LoggerKey).(Loggerer); ok {
@@ -96,24 +199,29 @@ func TransferCorrelationIDToOutgoingContext(ctx context.Context) (context.Contex
 	return newOutgoingCtx, nil
 }
 
-// AddCorrelationIDToOutgoingContext adds the correlation ID to the context
+// AddCorrelationIDToOutgoingContext adds the correlation ID to the context, replacing the
+// correlation ID of the existing outgoing metadata and keeping the other keys
 func AddCorrelationIDToOutgoingContext(ctx context.Context, correlationID string) context.Context {
-	existingMD, ok := metadata.FromOutgoingContext(ctx)
-	if !ok {
-		existingMD = metadata.New(map[string]string{})
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.New(map[string]string{})
 	}
-	newMD := metadata.New(map[string]string{
-		CorrelationIDKey: correlationID,
-	})
-	mergedMD := metadata.Join(existingMD, newMD)
-	return metadata.NewOutgoingContext(ctx, mergedMD)
+	md.Set(CorrelationIDKey, correlationID)
+	return metadata.NewOutgoingContext(ctx, md)
 }
 
-// AddCorrelationIDToIncomingContext adds the correlation ID to the context
+// AddCorrelationIDToIncomingContext adds the correlation ID to the context, replacing the
+// correlation ID of the existing incoming metadata and keeping the other keys
 func AddCorrelationIDToIncomingContext(ctx context.Context, correlationID string) context.Context {
-	md := metadata.New(map[string]string{
-		CorrelationIDKey: correlationID,
-	})
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.New(map[string]string{})
+	}
+	md.Set(CorrelationIDKey, correlationID)
 	return metadata.NewIncomingContext(ctx, md)
 }
 
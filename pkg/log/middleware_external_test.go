@@ -0,0 +1,46 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quadev-ltd/qd-common/pkg/log"
+	"github.com/quadev-ltd/qd-common/pkg/log/mock"
+)
+
+// The mock imports the package for its fields, so the tests using it are external
+
+func TestGetLoggerFromContext(t *testing.T) {
+	t.Run("Context_With_Logger", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		defer controller.Finish()
+
+		mockLogger := mock.NewMockLoggerer(controller)
+		ctx := context.WithValue(context.Background(), log.LoggerKey, mockLogger)
+
+		logger, err := log.GetLoggerFromContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, mockLogger, logger, "Expected to get the mock logger from context")
+	})
+
+	t.Run("Context_Without_Logger", func(t *testing.T) {
+		ctx := context.Background()
+
+		logger, err := log.GetLoggerFromContext(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, "Logger not found in context", err.Error())
+		assert.Nil(t, logger, "Expected to get nil when the logger is not present in context")
+	})
+
+	t.Run("Context_With_Non_Loggerer_Value_For_LoggerKey", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), log.LoggerKey, "not-a-logger")
+
+		logger, err := log.GetLoggerFromContext(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, "Logger not found in context", err.Error())
+		assert.Nil(t, logger, "Expected to get nil when the logger is not present in context")
+	})
+}
@@ -1,48 +1,20 @@
 package log
 
 import (
+	"bytes"
 	"context"
+	"net"
 	"testing"
 
-	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
-
-	"github.com/quadev-ltd/qd-common/pkg/log/mock"
+	"google.golang.org/grpc/test/bufconn"
 )
 
-func TestGetLoggerFromContext(t *testing.T) {
-	t.Run("Context_With_Logger", func(t *testing.T) {
-		controller := gomock.NewController(t)
-		defer controller.Finish()
-
-		mockLogger := mock.NewMockLoggerer(controller)
-		ctx := context.WithValue(context.Background(), LoggerKey, mockLogger)
-
-		logger, err := GetLoggerFromContext(ctx)
-		assert.NoError(t, err)
-		assert.Equal(t, mockLogger, logger, "Expected to get the mock logger from context")
-	})
-
-	t.Run("Context_Without_Logger", func(t *testing.T) {
-		ctx := context.Background()
-
-		logger, err := GetLoggerFromContext(ctx)
-		assert.Error(t, err)
-		assert.Equal(t, "Logger not found in context", err.Error())
-		assert.Nil(t, logger, "Expected to get nil when the logger is not present in context")
-	})
-
-	t.Run("Context_With_Non_Loggerer_Value_For_LoggerKey", func(t *testing.T) {
-		ctx := context.WithValue(context.Background(), LoggerKey, "not-a-logger")
-
-		logger, err := GetLoggerFromContext(ctx)
-		assert.Error(t, err)
-		assert.Equal(t, "Logger not found in context", err.Error())
-		assert.Nil(t, logger, "Expected to get nil when the logger is not present in context")
-	})
-}
-
 func TestAddCorrelationIDToContext(t *testing.T) {
 	t.Run("AddCorrelationIDToContext_Add_Correlation_ID", func(t *testing.T) {
 		correlationID := "test-correlation-id"
@@ -105,3 +77,204 @@ func TestGetCorrelationIDFromContext(t *testing.T) {
 		assert.Equal(t, "Correlation ID not found in metadata", err.Error(), "Expected an error when multiple correlation IDs are present")
 	})
 }
+
+func newBufconnHealthClient(t *testing.T, serverOptions ...grpc.ServerOption) grpc_health_v1.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(serverOptions...)
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { connection.Close() })
+	return grpc_health_v1.NewHealthClient(connection)
+}
+
+func TestCreateLoggerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	t.Run("Existing_Correlation_ID", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		interceptor := CreateLoggerInterceptor(NewLogFactory("development", WithOutput(buffer)))
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+			CorrelationIDKey, "test-correlation-id",
+			"authorization", "Bearer token",
+		))
+
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			logger, err := GetLoggerFromContext(ctx)
+			assert.NoError(t, err)
+			logger.Info("handler message")
+			outgoingMD, _ := metadata.FromOutgoingContext(ctx)
+			assert.Equal(t, []string{"test-correlation-id"}, outgoingMD.Get(CorrelationIDKey))
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"correlation_id":"test-correlation-id"`)
+		assert.Contains(t, buffer.String(), `"message":"handler message"`)
+	})
+
+	t.Run("Generates_Missing_Correlation_ID", func(t *testing.T) {
+		interceptor := CreateLoggerInterceptor(NewLogFactory("development", WithOutput(&bytes.Buffer{})))
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			correlationID, err := GetCorrelationIDFromContext(ctx)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, *correlationID)
+			incomingMD, _ := metadata.FromIncomingContext(ctx)
+			assert.Equal(t, []string{"Bearer token"}, incomingMD.Get("authorization"))
+			outgoingMD, _ := metadata.FromOutgoingContext(ctx)
+			assert.Equal(t, []string{*correlationID}, outgoingMD.Get(CorrelationIDKey))
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Echoes_Correlation_ID_Header", func(t *testing.T) {
+		client := newBufconnHealthClient(
+			t,
+			grpc.UnaryInterceptor(CreateLoggerInterceptor(NewLogFactory("development", WithOutput(&bytes.Buffer{})))),
+		)
+
+		var header metadata.MD
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}, grpc.Header(&header))
+		assert.NoError(t, err)
+		assert.Len(t, header.Get(CorrelationIDKey), 1)
+		assert.NotEmpty(t, header.Get(CorrelationIDKey)[0])
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), CorrelationIDKey, "test-correlation-id")
+		_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.Header(&header))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test-correlation-id"}, header.Get(CorrelationIDKey))
+	})
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *testServerStream) SetHeader(md metadata.MD) error {
+	stream.header = metadata.Join(stream.header, md)
+	return nil
+}
+
+func TestCreateLoggerStreamInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream", IsServerStream: true}
+
+	t.Run("Logger_In_Stream_Context", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		interceptor := CreateLoggerStreamInterceptor(NewLogFactory("development", WithOutput(buffer)))
+		stream := &testServerStream{
+			ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(CorrelationIDKey, "test-correlation-id")),
+		}
+
+		err := interceptor(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+			logger, err := GetLoggerFromContext(stream.Context())
+			assert.NoError(t, err)
+			logger.Info("stream message")
+			outgoingMD, _ := metadata.FromOutgoingContext(stream.Context())
+			assert.Equal(t, []string{"test-correlation-id"}, outgoingMD.Get(CorrelationIDKey))
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test-correlation-id"}, stream.header.Get(CorrelationIDKey))
+		assert.Contains(t, buffer.String(), `"correlation_id":"test-correlation-id"`)
+		assert.Contains(t, buffer.String(), `"message":"stream message"`)
+	})
+
+	t.Run("Echoes_Generated_Correlation_ID_Header", func(t *testing.T) {
+		client := newBufconnHealthClient(
+			t,
+			grpc.StreamInterceptor(CreateLoggerStreamInterceptor(NewLogFactory("development", WithOutput(&bytes.Buffer{})))),
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+		assert.NoError(t, err)
+		header, err := stream.Header()
+
+		assert.NoError(t, err)
+		assert.Len(t, header.Get(CorrelationIDKey), 1)
+		assert.NotEmpty(t, header.Get(CorrelationIDKey)[0])
+	})
+}
+
+func TestCreateCorrelationIDClientInterceptor(t *testing.T) {
+	getOutgoingCorrelationIDs := func(ctx context.Context) []string {
+		var correlationIDs []string
+		interceptor := CreateCorrelationIDClientInterceptor()
+		err := interceptor(ctx, "/test.Service/Method", nil, nil, nil, func(
+			ctx context.Context,
+			method string,
+			req, reply interface{},
+			connection *grpc.ClientConn,
+			opts ...grpc.CallOption,
+		) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			correlationIDs = md.Get(CorrelationIDKey)
+			return nil
+		})
+		assert.NoError(t, err)
+		return correlationIDs
+	}
+
+	t.Run("Forwards_Incoming_Correlation_ID", func(t *testing.T) {
+		ctx := AddCorrelationIDToIncomingContext(context.Background(), "test-correlation-id")
+
+		assert.Equal(t, []string{"test-correlation-id"}, getOutgoingCorrelationIDs(ctx))
+	})
+
+	t.Run("Keeps_Outgoing_Correlation_ID", func(t *testing.T) {
+		ctx := AddCorrelationIDToIncomingContext(context.Background(), "incoming-correlation-id")
+		ctx = AddCorrelationIDToOutgoingContext(ctx, "outgoing-correlation-id")
+
+		assert.Equal(t, []string{"outgoing-correlation-id"}, getOutgoingCorrelationIDs(ctx))
+	})
+
+	t.Run("Generates_Missing_Correlation_ID", func(t *testing.T) {
+		correlationIDs := getOutgoingCorrelationIDs(context.Background())
+
+		assert.Len(t, correlationIDs, 1)
+		assert.NotEmpty(t, correlationIDs[0])
+	})
+}
+
+func TestCreateCorrelationIDClientStreamInterceptor(t *testing.T) {
+	interceptor := CreateCorrelationIDClientStreamInterceptor()
+	ctx := AddCorrelationIDToIncomingContext(context.Background(), "test-correlation-id")
+
+	_, err := interceptor(ctx, &grpc.StreamDesc{}, nil, "/test.Service/Stream", func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		connection *grpc.ClientConn,
+		method string,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Equal(t, []string{"test-correlation-id"}, md.Get(CorrelationIDKey))
+		return nil, nil
+	})
+
+	assert.NoError(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	log "github.com/quadev-ltd/qd-common/pkg/log"
 )
 
 // MockLoggerer is a mock of Loggerer interface.
@@ -33,38 +34,173 @@ func (m *MockLoggerer) EXPECT() *MockLoggererMockRecorder {
 	return m.recorder
 }
 
+// Debug mocks base method.
+func (m *MockLoggerer) Debug(message string, fields ...log.Field) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{message}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Debug", varargs...)
+}
+
+// Debug indicates an expected call of Debug.
+func (mr *MockLoggererMockRecorder) Debug(message interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{message}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debug", reflect.TypeOf((*MockLoggerer)(nil).Debug), varargs...)
+}
+
+// Debugf mocks base method.
+func (m *MockLoggerer) Debugf(format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Debugf", varargs...)
+}
+
+// Debugf indicates an expected call of Debugf.
+func (mr *MockLoggererMockRecorder) Debugf(format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debugf", reflect.TypeOf((*MockLoggerer)(nil).Debugf), varargs...)
+}
+
 // Error mocks base method.
-func (m *MockLoggerer) Error(err error, message string) {
+func (m *MockLoggerer) Error(err error, message string, fields ...log.Field) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Error", err, message)
+	varargs := []interface{}{err, message}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
 }
 
 // Error indicates an expected call of Error.
-func (mr *MockLoggererMockRecorder) Error(err, message interface{}) *gomock.Call {
+func (mr *MockLoggererMockRecorder) Error(err, message interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{err, message}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLoggerer)(nil).Error), varargs...)
+}
+
+// Errorf mocks base method.
+func (m *MockLoggerer) Errorf(err error, format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{err, format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Errorf", varargs...)
+}
+
+// Errorf indicates an expected call of Errorf.
+func (mr *MockLoggererMockRecorder) Errorf(err, format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{err, format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Errorf", reflect.TypeOf((*MockLoggerer)(nil).Errorf), varargs...)
+}
+
+// Fatal mocks base method.
+func (m *MockLoggerer) Fatal(err error, message string, fields ...log.Field) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{err, message}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Fatal", varargs...)
+}
+
+// Fatal indicates an expected call of Fatal.
+func (mr *MockLoggererMockRecorder) Fatal(err, message interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLoggerer)(nil).Error), err, message)
+	varargs := append([]interface{}{err, message}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fatal", reflect.TypeOf((*MockLoggerer)(nil).Fatal), varargs...)
 }
 
 // Info mocks base method.
-func (m *MockLoggerer) Info(message string) {
+func (m *MockLoggerer) Info(message string, fields ...log.Field) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Info", message)
+	varargs := []interface{}{message}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
 }
 
 // Info indicates an expected call of Info.
-func (mr *MockLoggererMockRecorder) Info(message interface{}) *gomock.Call {
+func (mr *MockLoggererMockRecorder) Info(message interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLoggerer)(nil).Info), message)
+	varargs := append([]interface{}{message}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLoggerer)(nil).Info), varargs...)
+}
+
+// Infof mocks base method.
+func (m *MockLoggerer) Infof(format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Infof", varargs...)
+}
+
+// Infof indicates an expected call of Infof.
+func (mr *MockLoggererMockRecorder) Infof(format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Infof", reflect.TypeOf((*MockLoggerer)(nil).Infof), varargs...)
 }
 
 // Warn mocks base method.
-func (m *MockLoggerer) Warn(message string) {
+func (m *MockLoggerer) Warn(message string, fields ...log.Field) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Warn", message)
+	varargs := []interface{}{message}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Warn", varargs...)
 }
 
 // Warn indicates an expected call of Warn.
-func (mr *MockLoggererMockRecorder) Warn(message interface{}) *gomock.Call {
+func (mr *MockLoggererMockRecorder) Warn(message interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{message}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockLoggerer)(nil).Warn), varargs...)
+}
+
+// Warnf mocks base method.
+func (m *MockLoggerer) Warnf(format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Warnf", varargs...)
+}
+
+// Warnf indicates an expected call of Warnf.
+func (mr *MockLoggererMockRecorder) Warnf(format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warnf", reflect.TypeOf((*MockLoggerer)(nil).Warnf), varargs...)
+}
+
+// With mocks base method.
+func (m *MockLoggerer) With(fields ...log.Field) log.Loggerer {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "With", varargs...)
+	ret0, _ := ret[0].(log.Loggerer)
+	return ret0
+}
+
+// With indicates an expected call of With.
+func (mr *MockLoggererMockRecorder) With(fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockLoggerer)(nil).Warn), message)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "With", reflect.TypeOf((*MockLoggerer)(nil).With), fields...)
 }
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFileWriter writes to a file which is rotated when it reaches its maximum size,
+// the rotated files are renamed to path.1, path.2... keeping the latest maxBackups
+type RotatingFileWriter struct {
+	mutex      sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	closed     bool
+}
+
+var _ io.WriteCloser = &RotatingFileWriter{}
+
+// NewRotatingFileWriter creates a writer rotating the file at the path when it reaches maxSize bytes,
+// the file is opened on the first write
+func NewRotatingFileWriter(path string, maxSize int64, maxBackups int) *RotatingFileWriter {
+	return &RotatingFileWriter{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+}
+
+// Write writes the entry to the file, rotating it first when the entry does not fit,
+// it fails with os.ErrClosed once the writer is closed
+func (writer *RotatingFileWriter) Write(entry []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.closed {
+		return 0, os.ErrClosed
+	}
+	if writer.file == nil {
+		if err := writer.open(); err != nil {
+			return 0, err
+		}
+	}
+	if writer.size > 0 && writer.size+int64(len(entry)) > writer.maxSize {
+		if err := writer.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	written, err := writer.file.Write(entry)
+	writer.size += int64(written)
+	return written, err
+}
+
+// Close closes the file, the writer cannot be written to afterwards
+func (writer *RotatingFileWriter) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.closed = true
+	if writer.file == nil {
+		return nil
+	}
+	err := writer.file.Close()
+	writer.file = nil
+	return err
+}
+
+func (writer *RotatingFileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(writer.path), 0o755); err != nil {
+		return fmt.Errorf("Failed to create log directory: %v", err)
+	}
+	file, err := os.OpenFile(writer.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("Failed to open log file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("Failed to read log file size: %v", err)
+	}
+	writer.file = file
+	writer.size = info.Size()
+	return nil
+}
+
+func (writer *RotatingFileWriter) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", writer.path, index)
+}
+
+// rotate shifts the backups, dropping the oldest, and reopens the file, the mutex must be held
+func (writer *RotatingFileWriter) rotate() error {
+	if err := writer.file.Close(); err != nil {
+		return fmt.Errorf("Failed to close log file: %v", err)
+	}
+	writer.file = nil
+	if writer.maxBackups > 0 {
+		os.Remove(writer.backupPath(writer.maxBackups))
+		for index := writer.maxBackups - 1; index > 0; index-- {
+			os.Rename(writer.backupPath(index), writer.backupPath(index+1))
+		}
+		if err := os.Rename(writer.path, writer.backupPath(1)); err != nil {
+			return fmt.Errorf("Failed to rotate log file: %v", err)
+		}
+	} else if err := os.Remove(writer.path); err != nil {
+		return fmt.Errorf("Failed to rotate log file: %v", err)
+	}
+	return writer.open()
+}
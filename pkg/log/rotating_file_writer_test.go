@@ -0,0 +1,65 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFileWriter(t *testing.T) {
+	t.Run("Rotates_And_Keeps_Backups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "logs", "app.log")
+		writer := NewRotatingFileWriter(path, 10, 2)
+		defer writer.Close()
+
+		for _, entry := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err := writer.Write([]byte(entry))
+			assert.NoError(t, err)
+		}
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "fourth\n", string(content))
+		content, err = os.ReadFile(path + ".1")
+		assert.NoError(t, err)
+		assert.Equal(t, "third\n", string(content))
+		content, err = os.ReadFile(path + ".2")
+		assert.NoError(t, err)
+		assert.Equal(t, "second\n", string(content))
+		assert.NoFileExists(t, path+".3")
+	})
+
+	t.Run("Appends_To_Existing_File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		err := os.WriteFile(path, []byte("existing\n"), 0o644)
+		assert.NoError(t, err)
+		writer := NewRotatingFileWriter(path, 1024, 1)
+		defer writer.Close()
+
+		_, err = writer.Write([]byte("entry\n"))
+
+		assert.NoError(t, err)
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "existing\nentry\n", string(content))
+	})
+
+	t.Run("Write_After_Close_Fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		writer := NewRotatingFileWriter(path, 1024, 1)
+		_, err := writer.Write([]byte("entry\n"))
+		assert.NoError(t, err)
+		assert.NoError(t, writer.Close())
+
+		_, err = writer.Write([]byte("late entry\n"))
+
+		assert.True(t, errors.Is(err, os.ErrClosed))
+		assert.NoError(t, writer.Close())
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "entry\n", string(content))
+	})
+}
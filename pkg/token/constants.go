@@ -9,5 +9,6 @@ const (
 	ResetPasswordTokenType     Type = "ResetPasswordTokenType"
 	AuthTokenType              Type = "AuthTokenType"
 	RefreshTokenType           Type = "RefreshTokenType"
+	ServiceTokenType           Type = "ServiceTokenType"
 	AllTokenType               Type = "AllTokenType"
 )